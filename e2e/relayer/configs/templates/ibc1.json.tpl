@@ -30,7 +30,11 @@
   "prover": {
     "@type": "/relayer.provers.qbft.config.ProverConfig",
     "consensus_type": "$CONSENSUS_TYPE",
-    "trusting_period": "336h",
-    "max_clock_drift": "30s"
+    "trusting_period_duration": "1209600s",
+    "max_clock_drift_duration": "30s",
+    "refresh_threshold_rate": {
+      "numerator": 1,
+      "denominator": 2
+    }
   }
 }
@@ -0,0 +1,183 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcClient is the client of a single endpoint, which sends multiple requests in a JSON-RPC batch
+type rpcClient struct {
+	*client.ETHClient
+}
+
+var _ ethClient = rpcClient{}
+
+// HeaderAndProofByNumber returns the header and the proof of the account at the block in a batch
+func (cl rpcClient) HeaderAndProofByNumber(ctx context.Context, number *big.Int, address common.Address, storageKeys [][]byte) (*gethtypes.Header, *client.StateProof, error) {
+	keys, err := toStorageHashes(storageKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		header *gethtypes.Header
+		proof  *proofResult
+	)
+	batch := []rpc.BatchElem{
+		{Method: "eth_getBlockByNumber", Args: []interface{}{toBlockNumArg(number), false}, Result: &header},
+		{Method: "eth_getProof", Args: []interface{}{address, keys, toBlockNumArg(number)}, Result: &proof},
+	}
+	if err := cl.Raw().BatchCallContext(ctx, batch); err != nil {
+		return nil, nil, err
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			return nil, nil, fmt.Errorf("%v failed: %v", elem.Method, elem.Error)
+		}
+	}
+	if header == nil || proof == nil {
+		return nil, nil, ethereum.NotFound
+	}
+	stateProof, err := proof.toStateProof()
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, stateProof, nil
+}
+
+// HeadersByNumbers returns the headers at the numbers in a batch
+func (cl rpcClient) HeadersByNumbers(ctx context.Context, numbers []*big.Int) ([]*gethtypes.Header, error) {
+	headers := make([]*gethtypes.Header, len(numbers))
+	batch := make([]rpc.BatchElem, len(numbers))
+	for i, number := range numbers {
+		batch[i] = rpc.BatchElem{Method: "eth_getBlockByNumber", Args: []interface{}{toBlockNumArg(number), false}, Result: &headers[i]}
+	}
+	if err := cl.Raw().BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("%v failed: number=%v: %v", elem.Method, numbers[i], elem.Error)
+		} else if headers[i] == nil {
+			return nil, fmt.Errorf("header not found: number=%v", numbers[i])
+		}
+	}
+	return headers, nil
+}
+
+// CallContractByNumbers returns the results of the call at the numbers in a batch
+func (cl rpcClient) CallContractByNumbers(ctx context.Context, msg ethereum.CallMsg, numbers []*big.Int) ([][]byte, error) {
+	results := make([]hexutil.Bytes, len(numbers))
+	batch := make([]rpc.BatchElem, len(numbers))
+	for i, number := range numbers {
+		batch[i] = rpc.BatchElem{Method: "eth_call", Args: []interface{}{toCallArg(msg), toBlockNumArg(number)}, Result: &results[i]}
+	}
+	if err := cl.Raw().BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+	res := make([][]byte, len(numbers))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("%v failed: number=%v: %v", elem.Method, numbers[i], elem.Error)
+		}
+		res[i] = results[i]
+	}
+	return res, nil
+}
+
+// proofResult is the result of eth_getProof
+type proofResult struct {
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	StorageProof []storageResult `json:"storageProof"`
+}
+
+type storageResult struct {
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// toStateProof converts the result into the same form as client.ETHClient.GetProof returns
+func (r *proofResult) toStateProof() (*client.StateProof, error) {
+	stateProof := &client.StateProof{
+		CodeHash:    r.CodeHash,
+		Nonce:       uint64(r.Nonce),
+		StorageHash: r.StorageHash,
+	}
+	if r.Balance != nil {
+		stateProof.Balance.Set(r.Balance.ToInt())
+	}
+	var err error
+	if stateProof.AccountProofRLP, err = encodeProofNodes(r.AccountProof); err != nil {
+		return nil, err
+	}
+	for _, storageProof := range r.StorageProof {
+		bz, err := encodeProofNodes(storageProof.Proof)
+		if err != nil {
+			return nil, err
+		}
+		stateProof.StorageProofRLP = append(stateProof.StorageProofRLP, bz)
+	}
+	return stateProof, nil
+}
+
+// encodeProofNodes encodes the trie nodes into an RLP list, which is decoded by decodeRLPProof
+func encodeProofNodes(nodes []hexutil.Bytes) ([]byte, error) {
+	var decoded [][][]byte
+	for _, node := range nodes {
+		var items [][]byte
+		if err := rlp.DecodeBytes(node, &items); err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, items)
+	}
+	return rlp.EncodeToBytes(decoded)
+}
+
+func toStorageHashes(storageKeys [][]byte) ([]common.Hash, error) {
+	hashes := []common.Hash{}
+	for _, key := range storageKeys {
+		var h common.Hash
+		if err := h.UnmarshalText(key); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, h)
+	}
+	return hashes, nil
+}
+
+// toCallArg converts the message into the argument of eth_call in the same way as ethclient, for the fields used by the prover
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+	return arg
+}
+
+// toBlockNumArg converts the block number into the argument of the RPC methods in the same way as ethclient
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	if number.Sign() >= 0 {
+		return hexutil.EncodeBig(number)
+	}
+	if number.IsInt64() {
+		return rpc.BlockNumber(number.Int64()).String()
+	}
+	return fmt.Sprintf("<invalid %d>", number)
+}
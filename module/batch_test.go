@@ -0,0 +1,114 @@
+package module
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// benchRoundTripLatency is the latency added to each HTTP request to the RPC server in the benchmarks
+const benchRoundTripLatency = time.Millisecond
+
+func BenchmarkHeaderAndProof(b *testing.B) {
+	cl := newRPCClientForBench(b)
+	ctx := context.Background()
+	number := big.NewInt(100)
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := cl.HeaderByNumber(ctx, number); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := cl.GetProof(common.Address{}, nil, number); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := cl.HeaderAndProofByNumber(ctx, number, common.Address{}, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkHeadersByNumbers(b *testing.B) {
+	cl := newRPCClientForBench(b)
+	ctx := context.Background()
+	var numbers []*big.Int
+	for i := 0; i < headerBatchSize; i++ {
+		numbers = append(numbers, big.NewInt(int64(i+1)))
+	}
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, number := range numbers {
+				if _, err := cl.HeaderByNumber(ctx, number); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := cl.HeadersByNumbers(ctx, numbers); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// benchEthAPI serves the same header and proof for any block
+type benchEthAPI struct {
+	header *gethtypes.Header
+	proof  *proofResult
+}
+
+func (api *benchEthAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (*gethtypes.Header, error) {
+	return api.header, nil
+}
+
+func (api *benchEthAPI) GetProof(ctx context.Context, address common.Address, storageKeys []common.Hash, number rpc.BlockNumber) (*proofResult, error) {
+	return api.proof, nil
+}
+
+func newRPCClientForBench(b *testing.B) rpcClient {
+	node, err := rlp.EncodeToBytes([][]byte{{0x20}, make([]byte, 70)})
+	if err != nil {
+		b.Fatal(err)
+	}
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", &benchEthAPI{
+		header: &gethtypes.Header{
+			Number:     big.NewInt(100),
+			Difficulty: big.NewInt(1),
+			Extra:      make([]byte, 512),
+		},
+		proof: &proofResult{
+			Balance:      (*hexutil.Big)(big.NewInt(0)),
+			AccountProof: []hexutil.Bytes{node, node, node, node, node},
+		},
+	}); err != nil {
+		b.Fatal(err)
+	}
+	httpSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(benchRoundTripLatency)
+		srv.ServeHTTP(w, r)
+	}))
+	b.Cleanup(httpSrv.Close)
+	b.Cleanup(srv.Stop)
+	cl, err := client.NewETHClient(httpSrv.URL)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return rpcClient{cl}
+}
@@ -0,0 +1,202 @@
+package module
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultHeaderCacheSize is the number of blocks whose headers are cached in memory by default
+const DefaultHeaderCacheSize = 128
+
+// DefaultHeaderDiskCacheSize is the number of the latest blocks whose headers are kept in the disk cache by default
+const DefaultHeaderDiskCacheSize = 1024
+
+// blockKey identifies a block by both its number and hash, so that a cached entry is never returned for another block at the same number
+type blockKey struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+func newBlockKey(header *gethtypes.Header) blockKey {
+	return blockKey{Number: header.Number.Uint64(), Hash: header.Hash()}
+}
+
+// headerCache caches the headers built by the prover and the parsed extra data of blocks.
+// The headers are also stored under `dir` if it is set, so that they survive restarts.
+// Only the headers of the latest `diskSize` blocks are kept there, and the older ones are removed as newer headers are added.
+type headerCache struct {
+	headers *lru.Cache[blockKey, *decodedHeader]
+	extras  *lru.Cache[blockKey, *ExtraData]
+	dir     string
+
+	diskSize uint64
+	mu       sync.Mutex
+	// latest is the highest block number of the headers stored under dir
+	latest uint64
+}
+
+func newHeaderCache(size int) *headerCache {
+	return &headerCache{
+		headers: lru.NewCache[blockKey, *decodedHeader](size),
+		extras:  lru.NewCache[blockKey, *ExtraData](size),
+	}
+}
+
+// enableDisk makes the cache store the headers of the latest `size` blocks under `dir`
+func (c *headerCache) enableDisk(dir string, size uint64) error {
+	if size == 0 {
+		return fmt.Errorf("the size of the header disk cache must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create the header cache directory: %v", err)
+	}
+	c.dir = dir
+	c.diskSize = size
+	return nil
+}
+
+// getHeader returns a copy of the cached header of the block, so that the caller can set its trusted height
+func (c *headerCache) getHeader(key blockKey) (*decodedHeader, bool) {
+	if header, ok := c.headers.Get(key); ok {
+		return header.copy(), true
+	}
+	if c.dir == "" {
+		return nil, false
+	}
+	bz, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var header Header
+	if err := header.Unmarshal(bz); err != nil || header.ValidateBasic() != nil {
+		// the file is broken, so it is overwritten by the next addHeader
+		return nil, false
+	}
+	decoded, err := decodeHeader(&header)
+	if err != nil {
+		return nil, false
+	}
+	c.headers.Add(key, decoded)
+	return decoded.copy(), true
+}
+
+func (c *headerCache) addHeader(key blockKey, header *decodedHeader) error {
+	h := header.copy()
+	c.headers.Add(key, h)
+	if c.dir == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key.Number+c.diskSize <= c.latest {
+		// the header would be removed by the next pruning
+		return nil
+	}
+	bz, err := h.header.Marshal()
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so that a partially written file is never read
+	tmp, err := os.CreateTemp(c.dir, "header-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return err
+	}
+	if key.Number > c.latest {
+		c.latest = key.Number
+		return c.pruneDisk()
+	}
+	return nil
+}
+
+// pruneDisk removes the headers of the blocks older than the latest `diskSize` blocks from the disk
+func (c *headerCache) pruneDisk() error {
+	if c.latest < c.diskSize {
+		return nil
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".bin")
+		if !ok {
+			continue
+		}
+		numberStr, _, ok := strings.Cut(name, "-")
+		if !ok {
+			continue
+		}
+		number, err := strconv.ParseUint(numberStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		if number+c.diskSize <= c.latest {
+			if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// headerCacheID returns the identifier of the settings under which the headers are built,
+// which names the disk cache directory so that the headers built under other settings are never reused
+func headerCacheID(config ProverConfig, ibcAddress common.Address) (string, error) {
+	settings := struct {
+		RevisionNumber           uint64           `json:"revision_number"`
+		ConsensusType            string           `json:"consensus_type"`
+		ConsensusForks           []*ConsensusFork `json:"consensus_forks"`
+		ValidatorContractAddress *common.Address  `json:"validator_contract_address"`
+		ValidatorContractSlot    uint64           `json:"validator_contract_slot"`
+		IBCAddress               common.Address   `json:"ibc_address"`
+	}{
+		RevisionNumber:        config.RevisionNumber,
+		ConsensusType:         config.ConsensusTypeAt(0),
+		ConsensusForks:        config.ConsensusForks,
+		ValidatorContractSlot: config.ValidatorContractSlot,
+		IBCAddress:            ibcAddress,
+	}
+	if config.UsesValidatorContract() {
+		contract := common.HexToAddress(config.ValidatorContractAddress)
+		settings.ValidatorContractAddress = &contract
+	}
+	bz, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(crypto.Keccak256(bz)[:8]), nil
+}
+
+func (c *headerCache) getExtra(key blockKey) (*ExtraData, bool) {
+	return c.extras.Get(key)
+}
+
+func (c *headerCache) addExtra(key blockKey, extra *ExtraData) {
+	c.extras.Add(key, extra)
+}
+
+func (c *headerCache) path(key blockKey) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%d-%s.bin", key.Number, key.Hash.Hex()))
+}
@@ -0,0 +1,154 @@
+package module
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestHeaderCacheDisk(t *testing.T) {
+	dir := t.TempDir()
+	c := newHeaderCache(DefaultHeaderCacheSize)
+	if err := c.enableDisk(dir, 4); err != nil {
+		t.Fatal(err)
+	}
+	key := func(number uint64) blockKey {
+		return blockKey{Number: number, Hash: common.BigToHash(new(big.Int).SetUint64(number))}
+	}
+	for i := uint64(1); i <= 10; i++ {
+		if err := c.addHeader(key(i), &decodedHeader{header: &Header{BesuHeaderRlp: []byte{byte(i)}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a header older than the kept blocks is not stored
+	if err := c.addHeader(key(3), &decodedHeader{header: &Header{BesuHeaderRlp: []byte{3}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("unexpected number of cached files: %v", len(entries))
+	}
+	for i := uint64(7); i <= 10; i++ {
+		if _, err := os.Stat(c.path(key(i))); err != nil {
+			t.Fatalf("header %v is not cached: %v", i, err)
+		}
+	}
+
+	// files that are not headers are kept
+	other := filepath.Join(dir, "other")
+	if err := os.WriteFile(other, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.addHeader(key(11), &decodedHeader{header: &Header{BesuHeaderRlp: []byte{11}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(c.path(key(7))); !os.IsNotExist(err) {
+		t.Fatalf("header 7 is not pruned: %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeaderCacheCopy(t *testing.T) {
+	chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+	pr := newTestProver(t, chain)
+	chain.MineN(2)
+	h, err := pr.getHeader(context.Background(), chain.Head().Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, ok := pr.cache.getHeader(newBlockKey(chain.Head()))
+	if !ok {
+		t.Fatal("header is not cached")
+	}
+	// the copy shares the decoded eth header, but not the trusted height
+	if cached.ethHeader != h.ethHeader {
+		t.Fatal("eth header is decoded again")
+	}
+	cached.header.TrustedHeight = pr.newHeight(1)
+	if again, _ := pr.cache.getHeader(newBlockKey(chain.Head())); !again.header.TrustedHeight.IsZero() {
+		t.Fatalf("trusted height of the cached header is modified: %v", again.header.TrustedHeight)
+	}
+}
+
+func TestHeaderDiskCacheSettings(t *testing.T) {
+	config := newTestProverConfig(QBFTConsensusType)
+	config.HeaderDiskCache = true
+	chain := newTestChain(t, config, 4)
+	chain.MineN(2)
+	url := newTestRPCServer(t, chain)
+	home := t.TempDir()
+	key := newBlockKey(chain.Head())
+
+	newProver := func(config ProverConfig) *Prover {
+		pr := NewProver(newTestEthChain(t, url, testIBCAddress), config)
+		if err := pr.Init(home, time.Minute, nil, false); err != nil {
+			t.Fatal(err)
+		}
+		return pr
+	}
+	if _, err := newProver(config).getHeader(context.Background(), chain.Head().Number); err != nil {
+		t.Fatal(err)
+	}
+	// the header is reused after a restart with the same settings
+	if _, ok := newProver(config).cache.getHeader(key); !ok {
+		t.Fatal("the header is not cached on the disk")
+	}
+
+	for _, c := range []struct {
+		name   string
+		modify func(config *ProverConfig)
+	}{
+		{name: "revision number", modify: func(config *ProverConfig) { config.RevisionNumber = 1 }},
+		{name: "consensus forks", modify: func(config *ProverConfig) {
+			config.ConsensusForks = []*ConsensusFork{{Block: 100, ConsensusType: IBFT2ConsensusType}}
+		}},
+		{name: "validator contract", modify: func(config *ProverConfig) {
+			config.ValidatorContractAddress = "0x0000000000000000000000000000000000008888"
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			modified := config
+			c.modify(&modified)
+			if _, ok := newProver(modified).cache.getHeader(key); ok {
+				t.Fatal("the header built under other settings is reused")
+			}
+		})
+	}
+	// the slot of the validator contract and the IBC contract are a part of the settings as well
+	withContract := config
+	withContract.ValidatorContractAddress = "0x0000000000000000000000000000000000008888"
+	withSlot := withContract
+	withSlot.ValidatorContractSlot = 1
+	ids := make(map[string]bool)
+	for _, id := range []func() (string, error){
+		func() (string, error) { return headerCacheID(withContract, testIBCAddress) },
+		func() (string, error) { return headerCacheID(withSlot, testIBCAddress) },
+		func() (string, error) { return headerCacheID(withSlot, common.HexToAddress("0x01")) },
+	} {
+		id, err := id()
+		if err != nil {
+			t.Fatal(err)
+		} else if ids[id] {
+			t.Fatalf("the same id for different settings: %v", id)
+		}
+		ids[id] = true
+	}
+
+	// settings that do not affect the headers keep the cache
+	modified := config
+	modified.HeaderCacheSize = 1
+	if _, ok := newProver(modified).cache.getHeader(key); !ok {
+		t.Fatal("the header is not reused after an unrelated change")
+	}
+}
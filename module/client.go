@@ -0,0 +1,283 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// ethClient is the RPC client of the chain used by the prover
+type ethClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	CallContractByNumbers(ctx context.Context, msg ethereum.CallMsg, numbers []*big.Int) ([][]byte, error)
+	GetProof(address common.Address, storageKeys [][]byte, blockNumber *big.Int) (*client.StateProof, error)
+	HeaderAndProofByNumber(ctx context.Context, number *big.Int, address common.Address, storageKeys [][]byte) (*gethtypes.Header, *client.StateProof, error)
+	HeadersByNumbers(ctx context.Context, numbers []*big.Int) ([]*gethtypes.Header, error)
+}
+
+// quorumClient queries multiple endpoints of the chain and returns the result only if the responding endpoints agree on it
+type quorumClient struct {
+	endpoints []string
+	clients   []ethClient
+	// quorum is the minimum number of endpoints that must respond
+	quorum int
+}
+
+var _ ethClient = (*quorumClient)(nil)
+
+func newQuorumClient(endpoints []string, clients []ethClient, quorum int) (*quorumClient, error) {
+	if len(endpoints) != len(clients) {
+		return nil, fmt.Errorf("the number of endpoints and clients must be the same: %v != %v", len(endpoints), len(clients))
+	}
+	if quorum < 1 || quorum > len(clients) {
+		return nil, fmt.Errorf("quorum must be between 1 and the number of endpoints: quorum=%v endpoints=%v", quorum, len(clients))
+	}
+	return &quorumClient{endpoints: endpoints, clients: clients, quorum: quorum}, nil
+}
+
+// HeaderByNumber returns the header at the number, which must have the same hash on all the responding endpoints.
+// If `number` is nil or a block tag, the header at the lowest number of the tag among the endpoints is returned.
+func (qc *quorumClient) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	if number == nil || number.Sign() < 0 {
+		tag := number
+		headers, err := queryAll(qc, func(cl ethClient) (*gethtypes.Header, error) {
+			return nonNilHeader(cl.HeaderByNumber(ctx, tag))
+		})
+		if err != nil {
+			return nil, err
+		}
+		number = nil
+		for _, header := range headers {
+			if number == nil || header.Number.Cmp(number) < 0 {
+				number = header.Number
+			}
+		}
+	}
+	headers, err := queryAll(qc, func(cl ethClient) (*gethtypes.Header, error) {
+		return nonNilHeader(cl.HeaderByNumber(ctx, number))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.checkAgreement(len(headers), func(i int) bool {
+		return headers[i].Hash() == headers[0].Hash() && headers[i].Root == headers[0].Root
+	}); err != nil {
+		return nil, fmt.Errorf("header mismatch: number=%v: %v", number, err)
+	}
+	return headers[0], nil
+}
+
+// CallContract returns the result of the call, which must be the same on all the responding endpoints
+func (qc *quorumClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	results, err := queryAll(qc, func(cl ethClient) ([]byte, error) {
+		return cl.CallContract(ctx, msg, blockNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.checkAgreement(len(results), func(i int) bool {
+		return bytes.Equal(results[i], results[0])
+	}); err != nil {
+		return nil, fmt.Errorf("call result mismatch: to=%v number=%v: %v", msg.To, blockNumber, err)
+	}
+	return results[0], nil
+}
+
+// CallContractByNumbers returns the results of the call at the numbers, which must be the same on all the responding endpoints
+func (qc *quorumClient) CallContractByNumbers(ctx context.Context, msg ethereum.CallMsg, numbers []*big.Int) ([][]byte, error) {
+	if len(numbers) == 0 {
+		return nil, nil
+	}
+	results, err := queryAll(qc, func(cl ethClient) ([][]byte, error) {
+		return cl.CallContractByNumbers(ctx, msg, numbers)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.checkAgreement(len(results), func(i int) bool {
+		if len(results[i]) != len(numbers) || len(results[0]) != len(numbers) {
+			return false
+		}
+		for j := range numbers {
+			if !bytes.Equal(results[i][j], results[0][j]) {
+				return false
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("call result mismatch: to=%v numbers=%v-%v: %v", msg.To, numbers[0], numbers[len(numbers)-1], err)
+	} else if len(results[0]) != len(numbers) {
+		return nil, fmt.Errorf("call results are missing: to=%v numbers=%v-%v", msg.To, numbers[0], numbers[len(numbers)-1])
+	}
+	return results[0], nil
+}
+
+// GetProof returns the proof, which must be the same on all the responding endpoints
+func (qc *quorumClient) GetProof(address common.Address, storageKeys [][]byte, blockNumber *big.Int) (*client.StateProof, error) {
+	proofs, err := queryAll(qc, func(cl ethClient) (*client.StateProof, error) {
+		proof, err := cl.GetProof(address, storageKeys, blockNumber)
+		if err == nil && proof == nil {
+			err = fmt.Errorf("no proof is returned")
+		}
+		return proof, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := qc.checkAgreement(len(proofs), func(i int) bool {
+		return equalStateProofs(proofs[i], proofs[0])
+	}); err != nil {
+		return nil, fmt.Errorf("proof mismatch: address=%v number=%v: %v", address, blockNumber, err)
+	}
+	return proofs[0], nil
+}
+
+// HeaderAndProofByNumber returns the header and the proof, which must be the same on all the responding endpoints.
+// If `number` is nil or a block tag, it is resolved in the same way as HeaderByNumber.
+func (qc *quorumClient) HeaderAndProofByNumber(ctx context.Context, number *big.Int, address common.Address, storageKeys [][]byte) (*gethtypes.Header, *client.StateProof, error) {
+	if number == nil || number.Sign() < 0 {
+		header, err := qc.HeaderByNumber(ctx, number)
+		if err != nil {
+			return nil, nil, err
+		}
+		number = header.Number
+	}
+	type result struct {
+		header *gethtypes.Header
+		proof  *client.StateProof
+	}
+	results, err := queryAll(qc, func(cl ethClient) (result, error) {
+		header, proof, err := cl.HeaderAndProofByNumber(ctx, number, address, storageKeys)
+		if err == nil && (header == nil || proof == nil) {
+			err = fmt.Errorf("no header or proof is returned: number=%v", number)
+		}
+		return result{header, proof}, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := qc.checkAgreement(len(results), func(i int) bool {
+		return results[i].header.Hash() == results[0].header.Hash() && equalStateProofs(results[i].proof, results[0].proof)
+	}); err != nil {
+		return nil, nil, fmt.Errorf("header or proof mismatch: number=%v address=%v: %v", number, address, err)
+	}
+	return results[0].header, results[0].proof, nil
+}
+
+// HeadersByNumbers returns the headers, which must have the same hashes on all the responding endpoints.
+// A response that lacks any of the headers, e.g. a short batch response or a null block of a lagging node, is a disagreement.
+func (qc *quorumClient) HeadersByNumbers(ctx context.Context, numbers []*big.Int) ([]*gethtypes.Header, error) {
+	if len(numbers) == 0 {
+		return nil, nil
+	}
+	results, err := queryAll(qc, func(cl ethClient) ([]*gethtypes.Header, error) {
+		return cl.HeadersByNumbers(ctx, numbers)
+	})
+	if err != nil {
+		return nil, err
+	}
+	complete := func(headers []*gethtypes.Header) bool {
+		if len(headers) != len(numbers) {
+			return false
+		}
+		for j, header := range headers {
+			if header == nil || header.Number == nil || header.Number.Cmp(numbers[j]) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	if err := qc.checkAgreement(len(results), func(i int) bool {
+		if !complete(results[0]) || !complete(results[i]) {
+			return false
+		}
+		for j := range numbers {
+			if results[i][j].Hash() != results[0][j].Hash() {
+				return false
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("header mismatch: numbers=%v-%v: %v", numbers[0], numbers[len(numbers)-1], err)
+	} else if !complete(results[0]) {
+		// a single response is not compared with any other
+		return nil, fmt.Errorf("headers are missing: numbers=%v-%v", numbers[0], numbers[len(numbers)-1])
+	}
+	return results[0], nil
+}
+
+// checkAgreement returns an error if any of the `n` results does not agree with the first one
+func (qc *quorumClient) checkAgreement(n int, agrees func(i int) bool) error {
+	for i := 1; i < n; i++ {
+		if !agrees(i) {
+			return fmt.Errorf("the endpoints returned different results")
+		}
+	}
+	return nil
+}
+
+// queryAll queries all the endpoints concurrently and returns the results of the responding ones.
+// It fails if fewer endpoints than the quorum respond.
+func queryAll[T any](qc *quorumClient, query func(cl ethClient) (T, error)) ([]T, error) {
+	var (
+		results = make([]T, len(qc.clients))
+		errs    = make([]error, len(qc.clients))
+		wg      sync.WaitGroup
+	)
+	for i, cl := range qc.clients {
+		wg.Add(1)
+		go func(i int, cl ethClient) {
+			defer wg.Done()
+			results[i], errs[i] = query(cl)
+		}(i, cl)
+	}
+	wg.Wait()
+
+	var (
+		responses []T
+		failures  []error
+	)
+	for i := range qc.clients {
+		if errs[i] != nil {
+			failures = append(failures, fmt.Errorf("%v: %v", qc.endpoints[i], errs[i]))
+			continue
+		}
+		responses = append(responses, results[i])
+	}
+	if len(responses) < qc.quorum {
+		return nil, fmt.Errorf("only %v endpoints responded, which is less than the quorum %v: %v", len(responses), qc.quorum, errors.Join(failures...))
+	}
+	return responses, nil
+}
+
+// nonNilHeader returns an error instead of a nil header, or a header without a number, without an error
+func nonNilHeader(header *gethtypes.Header, err error) (*gethtypes.Header, error) {
+	if err == nil && (header == nil || header.Number == nil) {
+		return nil, fmt.Errorf("no header is returned")
+	}
+	return header, err
+}
+
+func equalStateProofs(a, b *client.StateProof) bool {
+	if a.StorageHash != b.StorageHash || a.CodeHash != b.CodeHash || a.Nonce != b.Nonce || a.Balance.Cmp(&b.Balance) != 0 {
+		return false
+	}
+	if !bytes.Equal(a.AccountProofRLP, b.AccountProofRLP) || len(a.StorageProofRLP) != len(b.StorageProofRLP) {
+		return false
+	}
+	for i := range a.StorageProofRLP {
+		if !bytes.Equal(a.StorageProofRLP[i], b.StorageProofRLP[i]) {
+			return false
+		}
+	}
+	return true
+}
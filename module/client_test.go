@@ -0,0 +1,199 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeEthClient returns the headers of `chain` and the fixed proof, or `err` on every query
+type fakeEthClient struct {
+	chain map[uint64]*gethtypes.Header
+	proof *client.StateProof
+	err   error
+}
+
+var _ ethClient = (*fakeEthClient)(nil)
+
+func newFakeChain(root common.Hash, numbers ...uint64) map[uint64]*gethtypes.Header {
+	chain := make(map[uint64]*gethtypes.Header)
+	for _, n := range numbers {
+		chain[n] = &gethtypes.Header{Number: new(big.Int).SetUint64(n), Root: root, Difficulty: big.NewInt(1)}
+	}
+	return chain
+}
+
+func (cl *fakeEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	if cl.err != nil {
+		return nil, cl.err
+	}
+	if number == nil || number.Sign() < 0 {
+		var latest *gethtypes.Header
+		for _, h := range cl.chain {
+			if latest == nil || h.Number.Cmp(latest.Number) > 0 {
+				latest = h
+			}
+		}
+		return latest, nil
+	}
+	return cl.chain[number.Uint64()], nil
+}
+
+func (cl *fakeEthClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if cl.err != nil {
+		return nil, cl.err
+	}
+	return cl.proof.StorageProofRLP[0], nil
+}
+
+func (cl *fakeEthClient) CallContractByNumbers(ctx context.Context, msg ethereum.CallMsg, numbers []*big.Int) ([][]byte, error) {
+	if cl.err != nil {
+		return nil, cl.err
+	}
+	var results [][]byte
+	for range numbers {
+		results = append(results, cl.proof.StorageProofRLP[0])
+	}
+	return results, nil
+}
+
+func (cl *fakeEthClient) GetProof(address common.Address, storageKeys [][]byte, blockNumber *big.Int) (*client.StateProof, error) {
+	if cl.err != nil {
+		return nil, cl.err
+	}
+	return cl.proof, nil
+}
+
+func (cl *fakeEthClient) HeaderAndProofByNumber(ctx context.Context, number *big.Int, address common.Address, storageKeys [][]byte) (*gethtypes.Header, *client.StateProof, error) {
+	header, err := cl.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, cl.proof, nil
+}
+
+func (cl *fakeEthClient) HeadersByNumbers(ctx context.Context, numbers []*big.Int) ([]*gethtypes.Header, error) {
+	if cl.err != nil {
+		return nil, cl.err
+	}
+	var headers []*gethtypes.Header
+	for _, n := range numbers {
+		h, ok := cl.chain[n.Uint64()]
+		if !ok {
+			// a lagging node returns a short batch response
+			break
+		}
+		headers = append(headers, h)
+	}
+	return headers, nil
+}
+
+func TestQuorumClient(t *testing.T) {
+	var (
+		root      = common.HexToHash("0x01")
+		otherRoot = common.HexToHash("0x02")
+		proof     = &client.StateProof{AccountProofRLP: []byte{1}, StorageProofRLP: [][]byte{{2}}}
+		other     = &client.StateProof{AccountProofRLP: []byte{1}, StorageProofRLP: [][]byte{{3}}}
+		down      = errors.New("connection refused")
+	)
+	good := func() *fakeEthClient {
+		return &fakeEthClient{chain: newFakeChain(root, 1, 2, 3), proof: proof}
+	}
+	const mismatch = "the endpoints returned different results"
+	for _, c := range []struct {
+		name    string
+		clients []*fakeEthClient
+		quorum  int
+		// headerErr and proofErr are the substrings of the errors of the queries of headers and proofs, or empty if they succeed
+		headerErr string
+		proofErr  string
+	}{
+		{name: "quorum reached", clients: []*fakeEthClient{good(), good(), good()}, quorum: 3},
+		{name: "endpoint error within quorum", clients: []*fakeEthClient{good(), {err: down}, good()}, quorum: 2},
+		{name: "quorum missed", clients: []*fakeEthClient{good(), {err: down}, {err: down}}, quorum: 2, headerErr: "less than the quorum 2", proofErr: "less than the quorum 2"},
+		{name: "mismatched header", clients: []*fakeEthClient{good(), {chain: newFakeChain(otherRoot, 1, 2, 3), proof: proof}}, quorum: 1, headerErr: mismatch},
+		{name: "mismatched proof", clients: []*fakeEthClient{good(), {chain: newFakeChain(root, 1, 2, 3), proof: other}}, quorum: 1, proofErr: mismatch},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			var (
+				endpoints []string
+				clients   []ethClient
+			)
+			for i, cl := range c.clients {
+				endpoints = append(endpoints, fmt.Sprintf("http://node%v:8545", i))
+				clients = append(clients, cl)
+			}
+			qc, err := newQuorumClient(endpoints, clients, c.quorum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			check := func(name string, err error, expected string) {
+				t.Helper()
+				if expected == "" {
+					if err != nil {
+						t.Fatalf("unexpected error of %v: %v", name, err)
+					}
+				} else if err == nil || !strings.Contains(err.Error(), expected) {
+					t.Fatalf("unexpected error of %v: expected=%q actual=%v", name, expected, err)
+				}
+			}
+			ctx := context.Background()
+
+			header, err := qc.HeaderByNumber(ctx, nil)
+			check("HeaderByNumber", err, c.headerErr)
+			if err == nil && header.Number.Uint64() != 3 {
+				t.Fatalf("unexpected header: expected=3 actual=%v", header.Number)
+			}
+			_, err = qc.HeadersByNumbers(ctx, []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+			check("HeadersByNumbers", err, c.headerErr)
+			_, err = qc.GetProof(common.Address{}, nil, big.NewInt(3))
+			check("GetProof", err, c.proofErr)
+			// the fake clients return the storage proof as the result of the call
+			_, err = qc.CallContractByNumbers(ctx, ethereum.CallMsg{}, []*big.Int{big.NewInt(1), big.NewInt(2)})
+			check("CallContractByNumbers", err, c.proofErr)
+			// either of a header and a proof can disagree
+			expected := c.headerErr
+			if expected == "" {
+				expected = c.proofErr
+			}
+			_, _, err = qc.HeaderAndProofByNumber(ctx, big.NewInt(3), common.Address{}, nil)
+			check("HeaderAndProofByNumber", err, expected)
+		})
+	}
+}
+
+func TestQuorumClientMissingHeaders(t *testing.T) {
+	root := common.HexToHash("0x01")
+	lagging := &fakeEthClient{chain: newFakeChain(root, 1, 2)}
+	for _, c := range []struct {
+		name    string
+		clients []ethClient
+	}{
+		{name: "lagging second", clients: []ethClient{&fakeEthClient{chain: newFakeChain(root, 1, 2, 3)}, lagging}},
+		{name: "lagging first", clients: []ethClient{lagging, &fakeEthClient{chain: newFakeChain(root, 1, 2, 3)}}},
+		{name: "lagging only", clients: []ethClient{lagging}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			qc, err := newQuorumClient(make([]string, len(c.clients)), c.clients, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// a short batch response must be an error, not a panic
+			if _, err := qc.HeadersByNumbers(context.Background(), []*big.Int{big.NewInt(2), big.NewInt(3)}); err == nil {
+				t.Fatal("missing headers are accepted")
+			}
+			// a null block is an endpoint failure
+			if _, err := qc.HeaderByNumber(context.Background(), big.NewInt(3)); len(c.clients) == 1 && err == nil {
+				t.Fatal("a null block is accepted")
+			}
+		})
+	}
+}
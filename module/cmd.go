@@ -0,0 +1,420 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagHeight        = "height"
+	flagTrustedHeight = "trusted-height"
+	flagInterval      = "interval"
+	flagBlocks        = "blocks"
+	flagAlertMissRate = "alert-miss-rate"
+)
+
+// qbftCmd returns the command to inspect the QBFT/IBFT 2.0 chains with the prover
+func qbftCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "qbft",
+		Short: "inspect QBFT and IBFT 2.0 chains",
+	}
+
+	cmd.AddCommand(
+		headerCmd(ctx),
+		verifyHeaderCmd(ctx),
+		clientStateCmd(ctx),
+		proveCmd(ctx),
+		watchMisbehaviourCmd(ctx),
+		livenessCmd(ctx),
+	)
+
+	return cmd
+}
+
+func headerCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "header [chain-id] [height]",
+		Short: "Show the header at the height with its validators and seals",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr, err := getProver(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			height, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			info, err := pr.inspectHeader(cmd.Context(), height)
+			if err != nil {
+				return err
+			}
+			return printJSON(info)
+		},
+	}
+}
+
+func verifyHeaderCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-header [chain-id] [height]",
+		Short: "Verify the seals of the header at the height as the light client does",
+		Long: `Verify the seals of the header at the height as the light client does.
+If --trusted-height is given, the header is also verified against the consensus state at the trusted height.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr, err := getProver(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			height, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			trustedHeight, err := cmd.Flags().GetUint64(flagTrustedHeight)
+			if err != nil {
+				return err
+			}
+			if err := pr.verifyHeaderAt(cmd.Context(), height, trustedHeight); err != nil {
+				return err
+			}
+			fmt.Printf("header at %v is valid\n", height)
+			return nil
+		},
+	}
+	cmd.Flags().Uint64(flagTrustedHeight, 0, "height of the trusted consensus state to verify the header against")
+	return cmd
+}
+
+func clientStateCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "client-state [chain-id]",
+		Short: "Show the initial client state and consensus state that would be created for the chain",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr, err := getProver(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			height, err := cmd.Flags().GetUint64(flagHeight)
+			if err != nil {
+				return err
+			}
+			var h exported.Height
+			if height > 0 {
+				h = pr.newHeight(int64(height))
+			}
+			clientState, consensusState, err := pr.CreateInitialLightClientState(h)
+			if err != nil {
+				return err
+			}
+			cs, err := ctx.Codec.MarshalJSON(clientState.(*ClientState))
+			if err != nil {
+				return err
+			}
+			cons, err := ctx.Codec.MarshalJSON(consensusState.(*ConsensusState))
+			if err != nil {
+				return err
+			}
+			return printJSON(map[string]json.RawMessage{
+				"client_state":    cs,
+				"consensus_state": cons,
+			})
+		},
+	}
+	cmd.Flags().Uint64(flagHeight, 0, "height of the initial state (the latest height if not given)")
+	return cmd
+}
+
+func proveCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "prove [chain-id] [path] [height]",
+		Short: "Show the proof of the commitment for the path at the height",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr, err := getProver(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			height, err := strconv.ParseInt(args[2], 10, 64)
+			if err != nil {
+				return err
+			}
+			commitment, err := pr.queryCommitment([]byte(args[1]), height)
+			if err != nil {
+				return err
+			}
+			return printJSON(struct {
+				Path        string        `json:"path"`
+				Height      int64         `json:"height"`
+				StorageKey  common.Hash   `json:"storage_key"`
+				StorageRoot common.Hash   `json:"storage_root"`
+				Commitment  common.Hash   `json:"commitment"`
+				Exists      bool          `json:"exists"`
+				Proof       hexutil.Bytes `json:"proof"`
+			}{
+				Path:        args[1],
+				Height:      height,
+				StorageKey:  commitment.StorageKey,
+				StorageRoot: commitment.StorageRoot,
+				Commitment:  commitment.Value,
+				Exists:      commitment.Value != (common.Hash{}),
+				Proof:       commitment.Proof,
+			})
+		},
+	}
+}
+
+func watchMisbehaviourCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch-misbehaviour [chain-id] [endpoint]...",
+		Short: "Watch the endpoints for conflicting headers and print the misbehaviour found",
+		Long: `Watch the endpoints for conflicting headers from the given height and print the misbehaviour found.
+The trusted height of the headers in the misbehaviour is set to --trusted-height, which is required
+as the consensus state at the height must exist in the client the misbehaviour is submitted to.`,
+		Args: cobra.MinimumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr, err := getProver(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			watcher, err := NewMisbehaviourWatcher(pr, args[1:])
+			if err != nil {
+				return err
+			}
+			height, err := cmd.Flags().GetUint64(flagHeight)
+			if err != nil {
+				return err
+			}
+			trustedHeight, err := cmd.Flags().GetUint64(flagTrustedHeight)
+			if err != nil {
+				return err
+			} else if trustedHeight == 0 {
+				return fmt.Errorf("--%s must not be zero", flagTrustedHeight)
+			}
+			interval, err := cmd.Flags().GetDuration(flagInterval)
+			if err != nil {
+				return err
+			}
+			return watcher.Watch(cmd.Context(), height, interval, func() (clienttypes.Height, error) {
+				return pr.newHeight(int64(trustedHeight)), nil
+			}, func(misbehaviour *Misbehaviour) error {
+				out, err := ctx.Codec.MarshalJSON(misbehaviour)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(out))
+				return nil
+			})
+		},
+	}
+	cmd.Flags().Uint64(flagHeight, 1, "height to start watching from")
+	cmd.Flags().Uint64(flagTrustedHeight, 0, "height of the trusted consensus state of the client the misbehaviour is submitted to")
+	cmd.Flags().Duration(flagInterval, 5*time.Second, "interval of polling the endpoints")
+	if err := cmd.MarkFlagRequired(flagTrustedHeight); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func livenessCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "liveness [chain-id]",
+		Short: "Show the seal participation of the validators in the latest blocks",
+		Long: `Show the seal participation of the validators in the --blocks blocks up to --height.
+The validators whose miss rate exceeds --alert-miss-rate are marked as alerted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr, err := getProver(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			height, err := cmd.Flags().GetUint64(flagHeight)
+			if err != nil {
+				return err
+			}
+			blocks, err := cmd.Flags().GetUint64(flagBlocks)
+			if err != nil {
+				return err
+			}
+			alertMissRate, err := cmd.Flags().GetFloat64(flagAlertMissRate)
+			if err != nil {
+				return err
+			}
+			report, err := pr.livenessReport(cmd.Context(), height, blocks, alertMissRate)
+			if err != nil {
+				return err
+			}
+			return printJSON(report)
+		},
+	}
+	cmd.Flags().Uint64(flagHeight, 0, "height of the last block (the latest height if not given)")
+	cmd.Flags().Uint64(flagBlocks, DefaultLivenessWindow, "number of the blocks to scan")
+	cmd.Flags().Float64(flagAlertMissRate, 0, "miss rate above which the validators are alerted (no alerts if not given)")
+	return cmd
+}
+
+func getProver(ctx *config.Context, chainID string) (*Prover, error) {
+	c, err := ctx.Config.GetChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	pr, ok := c.Prover.(*Prover)
+	if !ok {
+		return nil, fmt.Errorf("the prover of chain %v is not a QBFT prover: %T", chainID, c.Prover)
+	}
+	return pr, nil
+}
+
+func printJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// headerInfo is the human readable summary of a header
+type headerInfo struct {
+	Number        uint64          `json:"number"`
+	Hash          common.Hash     `json:"hash"`
+	ParentHash    common.Hash     `json:"parent_hash"`
+	StateRoot     common.Hash     `json:"state_root"`
+	Timestamp     uint64          `json:"timestamp"`
+	ConsensusType string          `json:"consensus_type"`
+	Round         uint64          `json:"round"`
+	Validators    []validatorInfo `json:"validators"`
+	Signed        int             `json:"signed"`
+	Threshold     int             `json:"threshold"`
+	SealReport    *SealReport     `json:"seal_report"`
+}
+
+type validatorInfo struct {
+	Address common.Address `json:"address"`
+	Signed  bool           `json:"signed"`
+}
+
+// livenessReportInfo is the seal participation of the validators in a range of blocks
+type livenessReportInfo struct {
+	From       uint64         `json:"from"`
+	To         uint64         `json:"to"`
+	Blocks     int            `json:"blocks"`
+	Validators []livenessInfo `json:"validators"`
+	Alerts     []string       `json:"alerts,omitempty"`
+}
+
+type livenessInfo struct {
+	ValidatorStats
+	Alerted bool `json:"alerted"`
+}
+
+// livenessReport scans the `blocks` blocks up to `height` and returns the seal participation of the validators in them
+func (pr *Prover) livenessReport(ctx context.Context, height, blocks uint64, alertMissRate float64) (*livenessReportInfo, error) {
+	if blocks == 0 {
+		return nil, fmt.Errorf("the number of blocks must be positive")
+	}
+	if height == 0 {
+		latest, err := pr.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		height = latest.Number.Uint64()
+	}
+	var from uint64
+	if height >= blocks {
+		from = height - blocks + 1
+	}
+	tracker := NewLivenessTracker(blocks)
+	if err := pr.ScanLiveness(ctx, tracker, from, height); err != nil {
+		return nil, err
+	}
+	from, to, count := tracker.Range()
+	report := &livenessReportInfo{From: from, To: to, Blocks: count}
+	for _, stats := range tracker.Stats() {
+		alerted := alertMissRate > 0 && stats.MissRate > alertMissRate
+		if alerted {
+			report.Alerts = append(report.Alerts, fmt.Sprintf("validator %v missed %.1f%% of the last %v blocks", stats.Address, stats.MissRate*100, stats.Blocks))
+		}
+		report.Validators = append(report.Validators, livenessInfo{ValidatorStats: stats, Alerted: alerted})
+	}
+	return report, nil
+}
+
+// inspectHeader returns the summary of the header at the height, including which validators sealed it
+func (pr *Prover) inspectHeader(ctx context.Context, height uint64) (*headerInfo, error) {
+	header, err := pr.client.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return nil, err
+	}
+	extra, err := pr.getExtraData(header)
+	if err != nil {
+		return nil, err
+	}
+	validators, _, err := pr.getValidators(ctx, header, extra)
+	if err != nil {
+		return nil, err
+	}
+	headerBytes, err := pr.encodeHeaderWithoutSeals(*header, *extra)
+	if err != nil {
+		return nil, err
+	}
+	recovered, report := recoverSeals(headerBytes, extra.Seals)
+	orderedSeals, count := orderSeals(validators, recovered, report)
+	info := &headerInfo{
+		Number:        height,
+		Hash:          crypto.Keccak256Hash(headerBytes),
+		ParentHash:    header.ParentHash,
+		StateRoot:     header.Root,
+		Timestamp:     header.Time,
+		ConsensusType: pr.config.ConsensusTypeAt(height),
+		Round:         new(big.Int).SetBytes(extra.Round).Uint64(),
+		Signed:        count,
+		Threshold:     len(validators) * 2 / 3,
+		SealReport:    report,
+	}
+	for i, val := range validators {
+		info.Validators = append(info.Validators, validatorInfo{Address: val, Signed: orderedSeals[i] != nil})
+	}
+	return info, nil
+}
+
+// verifyHeaderAt verifies the header at the height as the light client does.
+// If `trustedHeight` is not zero, the header is verified against the consensus state at `trustedHeight`.
+func (pr *Prover) verifyHeaderAt(ctx context.Context, height, trustedHeight uint64) error {
+	decoded, err := pr.getHeader(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return err
+	}
+	header := decoded.header
+	clientState, err := pr.newClientState(int64(height))
+	if err != nil {
+		return err
+	}
+	if trustedHeight == 0 {
+		consState, err := clientState.consensusStateFromEthHeader(header, decoded.ethHeader)
+		if err != nil {
+			return err
+		}
+		return verifyCommitSeals(bytesToAddresses(consState.Validators), header.Seals, crypto.Keccak256(header.BesuHeaderRlp))
+	}
+	header.TrustedHeight = pr.newHeight(int64(trustedHeight))
+	_, trustedConsState, err := pr.CreateInitialLightClientState(header.TrustedHeight)
+	if err != nil {
+		return err
+	}
+	_, err = clientState.verifyHeader(trustedConsState.(*ConsensusState), header, time.Now())
+	return err
+}
@@ -1,10 +1,17 @@
 package module
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
 	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/relay/ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hyperledger-labs/yui-relayer/core"
 )
 
@@ -13,6 +20,12 @@ const (
 	IBFT2ConsensusType = "ibft2"
 )
 
+const (
+	LatestBlockTag    = "latest"
+	SafeBlockTag      = "safe"
+	FinalizedBlockTag = "finalized"
+)
+
 var _ core.ProverConfig = (*ProverConfig)(nil)
 
 func (c ProverConfig) Build(chain core.Chain) (core.Prover, error) {
@@ -23,45 +36,241 @@ func (c ProverConfig) Build(chain core.Chain) (core.Prover, error) {
 	return NewProver(chain_, c), nil
 }
 
+// Validate reports all the problems of the config at once
 func (c ProverConfig) Validate() error {
-	if c.ConsensusType != "" && c.ConsensusType != QBFTConsensusType && c.ConsensusType != IBFT2ConsensusType {
-		return fmt.Errorf("invalid consensus type: %s", c.ConsensusType)
+	var errs []error
+	if c.ConsensusType != "" && !isValidConsensusType(c.ConsensusType) {
+		errs = append(errs, fmt.Errorf("invalid consensus type: %s", c.ConsensusType))
 	}
-	if c.TrustingPeriod != "" {
-		if _, err := time.ParseDuration(c.TrustingPeriod); err != nil {
-			return fmt.Errorf("invalid trusting period: %s", c.TrustingPeriod)
+	for i, fork := range c.ConsensusForks {
+		if !isValidConsensusType(fork.ConsensusType) {
+			errs = append(errs, fmt.Errorf("invalid consensus type of consensus_forks[%v]: %s", i, fork.ConsensusType))
+		}
+		if i > 0 && fork.Block <= c.ConsensusForks[i-1].Block {
+			errs = append(errs, fmt.Errorf("config attribute \"consensus_forks\" must be in strictly ascending order of block: consensus_forks[%v].block=%v consensus_forks[%v].block=%v", i-1, c.ConsensusForks[i-1].Block, i, fork.Block))
 		}
 	}
-	if c.MaxClockDrift != "" {
-		if _, err := time.ParseDuration(c.MaxClockDrift); err != nil {
-			return fmt.Errorf("invalid max clock drift: %s", c.MaxClockDrift)
+	trustingPeriod, trustingPeriodErr := c.GetTrustingPeriod()
+	if trustingPeriodErr != nil {
+		errs = append(errs, trustingPeriodErr)
+	} else if trustingPeriod <= 0 {
+		errs = append(errs, fmt.Errorf("config attribute \"trusting_period_duration\" must be positive: %v", trustingPeriod))
+	}
+	maxClockDrift, maxClockDriftErr := c.GetMaxClockDrift()
+	if maxClockDriftErr != nil {
+		errs = append(errs, maxClockDriftErr)
+	} else if maxClockDrift < 0 {
+		errs = append(errs, fmt.Errorf("config attribute \"max_clock_drift_duration\" must not be negative: %v", maxClockDrift))
+	}
+	if trustingPeriodErr == nil && maxClockDriftErr == nil && trustingPeriod > 0 && maxClockDrift >= trustingPeriod {
+		errs = append(errs, fmt.Errorf("max clock drift must be less than the trusting period: max_clock_drift=%v trusting_period=%v", maxClockDrift, trustingPeriod))
+	}
+	if c.RefreshThresholdRate != nil {
+		if c.RefreshThresholdRate.Denominator == 0 {
+			errs = append(errs, fmt.Errorf("config attribute \"refresh_threshold_rate.denominator\" must not be zero"))
+		}
+		if c.RefreshThresholdRate.Numerator == 0 {
+			errs = append(errs, fmt.Errorf("config attribute \"refresh_threshold_rate.numerator\" must not be zero"))
 		}
+		if c.RefreshThresholdRate.Numerator > c.RefreshThresholdRate.Denominator {
+			errs = append(errs, fmt.Errorf("config attribute \"refresh_threshold_rate\" must be less than or equal to 1.0: actual=%v/%v", c.RefreshThresholdRate.Numerator, c.RefreshThresholdRate.Denominator))
+		}
+	}
+	if c.IbcCommitmentsSlot != "" && c.IbcCommitmentsNamespace != "" {
+		errs = append(errs, fmt.Errorf("config attributes \"ibc_commitments_slot\" and \"ibc_commitments_namespace\" cannot be set at the same time"))
+	} else if _, err := c.GetIBCCommitmentsSlot(); err != nil {
+		errs = append(errs, err)
 	}
-	return nil
+	if c.ValidatorContractAddress != "" {
+		if !common.IsHexAddress(c.ValidatorContractAddress) {
+			errs = append(errs, fmt.Errorf("invalid validator contract address: %s", c.ValidatorContractAddress))
+		} else if common.HexToAddress(c.ValidatorContractAddress) == (common.Address{}) {
+			errs = append(errs, fmt.Errorf("config attribute \"validator_contract_address\" must not be the zero address"))
+		}
+		// Besu reads the validator set from the contract only in QBFT
+		if c.IsIBFT2At(0) {
+			errs = append(errs, fmt.Errorf("the validator contract cannot be used with %s", IBFT2ConsensusType))
+		}
+		for i, fork := range c.ConsensusForks {
+			if fork.ConsensusType == IBFT2ConsensusType {
+				errs = append(errs, fmt.Errorf("the validator contract cannot be used with %s of consensus_forks[%v]", IBFT2ConsensusType, i))
+			}
+		}
+	} else if c.ValidatorContractSlot != 0 {
+		errs = append(errs, fmt.Errorf("config attribute \"validator_contract_slot\" cannot be set without \"validator_contract_address\""))
+	}
+	for i, addr := range c.RpcAddrs {
+		if addr == "" {
+			errs = append(errs, fmt.Errorf("config attribute \"rpc_addrs[%v]\" must not be empty", i))
+		}
+	}
+	if c.RpcQuorum > uint32(len(c.RpcAddrs)+1) {
+		errs = append(errs, fmt.Errorf("config attribute \"rpc_quorum\" must not exceed the number of the endpoints: rpc_quorum=%v endpoints=%v", c.RpcQuorum, len(c.RpcAddrs)+1))
+	}
+	switch c.BlockTag {
+	case "", LatestBlockTag, SafeBlockTag, FinalizedBlockTag:
+	default:
+		errs = append(errs, fmt.Errorf("invalid block tag: %s", c.BlockTag))
+	}
+	return errors.Join(errs...)
+}
+
+// GetBlockTag returns the block number argument of the RPC methods corresponding to the block tag
+func (c ProverConfig) GetBlockTag() *big.Int {
+	switch c.BlockTag {
+	case SafeBlockTag:
+		return big.NewInt(int64(rpc.SafeBlockNumber))
+	case FinalizedBlockTag:
+		return big.NewInt(int64(rpc.FinalizedBlockNumber))
+	default:
+		return nil
+	}
+}
+
+// GetHeaderCacheSize returns the number of blocks whose headers are cached in memory
+func (c ProverConfig) GetHeaderCacheSize() int {
+	if c.HeaderCacheSize == 0 {
+		return DefaultHeaderCacheSize
+	}
+	return int(c.HeaderCacheSize)
 }
 
+// GetHeaderDiskCacheSize returns the number of the latest blocks whose headers are kept in the disk cache
+func (c ProverConfig) GetHeaderDiskCacheSize() uint64 {
+	if c.HeaderDiskCacheSize == 0 {
+		return DefaultHeaderDiskCacheSize
+	}
+	return c.HeaderDiskCacheSize
+}
+
+// GetLivenessWindow returns the number of the latest blocks over which the seal participation of the validators is tracked
+func (c ProverConfig) GetLivenessWindow() uint64 {
+	if c.LivenessWindow == 0 {
+		return DefaultLivenessWindow
+	}
+	return uint64(c.LivenessWindow)
+}
+
+// GetMaxTransitionSearchRange returns the maximum number of blocks searched for the validator set changes
+func (c ProverConfig) GetMaxTransitionSearchRange() uint64 {
+	if c.MaxTransitionSearchRange == 0 {
+		return DefaultMaxTransitionSearchRange
+	}
+	return c.MaxTransitionSearchRange
+}
+
+// GetRPCQuorum returns the minimum number of the endpoints that must respond, which defaults to 1
+func (c ProverConfig) GetRPCQuorum() int {
+	if c.RpcQuorum == 0 {
+		return 1
+	}
+	return int(c.RpcQuorum)
+}
+
+// ConsensusTypeAt returns the consensus type of the block, taking the consensus forks into account
+func (c ProverConfig) ConsensusTypeAt(height uint64) string {
+	consensusType := c.ConsensusType
+	if consensusType == "" {
+		consensusType = QBFTConsensusType
+	}
+	for _, fork := range c.ConsensusForks {
+		if fork.Block > height {
+			break
+		}
+		consensusType = fork.ConsensusType
+	}
+	return consensusType
+}
+
+// IsIBFT2At returns whether the block is produced by IBFT 2.0
+func (c ProverConfig) IsIBFT2At(height uint64) bool {
+	return c.ConsensusTypeAt(height) == IBFT2ConsensusType
+}
+
+// IsIBFT2 returns whether the genesis block is produced by IBFT 2.0.
+//
+// Deprecated: use IsIBFT2At, which takes the consensus forks into account.
 func (c ProverConfig) IsIBFT2() bool {
-	return c.ConsensusType == IBFT2ConsensusType
+	return c.IsIBFT2At(0)
 }
 
-func (c ProverConfig) GetTrustingPeriod() time.Duration {
-	if c.TrustingPeriod == "" {
-		return 0
+func isValidConsensusType(consensusType string) bool {
+	return consensusType == QBFTConsensusType || consensusType == IBFT2ConsensusType
+}
+
+// UsesValidatorContract returns whether the validator set is managed by the validator contract
+func (c ProverConfig) UsesValidatorContract() bool {
+	return c.ValidatorContractAddress != ""
+}
+
+// GetTrustingPeriod returns the trusting period from either trusting_period_duration or the deprecated trusting_period,
+// which is zero if neither is set
+func (c ProverConfig) GetTrustingPeriod() (time.Duration, error) {
+	return resolveDuration("trusting_period", c.TrustingPeriodDuration, c.TrustingPeriod)
+}
+
+// GetMaxClockDrift returns the max clock drift from either max_clock_drift_duration or the deprecated max_clock_drift,
+// which is zero if neither is set
+func (c ProverConfig) GetMaxClockDrift() (time.Duration, error) {
+	return resolveDuration("max_clock_drift", c.MaxClockDriftDuration, c.MaxClockDrift)
+}
+
+// resolveDuration returns the duration of the attribute `name`, which is set by either `d` or the deprecated string `s`.
+// The duration must be a whole number of seconds as the light client stores it in seconds.
+func resolveDuration(name string, d *time.Duration, s string) (time.Duration, error) {
+	var duration time.Duration
+	switch {
+	case d != nil && s != "":
+		return 0, fmt.Errorf("config attributes \"%s_duration\" and \"%s\" cannot be set at the same time", name, name)
+	case d != nil:
+		duration = *d
+	case s != "":
+		var err error
+		if duration, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid %s: %s", strings.ReplaceAll(name, "_", " "), s)
+		}
 	}
-	d, err := time.ParseDuration(c.TrustingPeriod)
-	if err != nil {
-		panic(err)
+	if duration%time.Second != 0 {
+		return 0, fmt.Errorf("config attribute \"%s\" must be a whole number of seconds: %v", name, duration)
 	}
-	return d
+	return duration, nil
 }
 
-func (c ProverConfig) GetMaxClockDrift() time.Duration {
-	if c.MaxClockDrift == "" {
-		return 0
+// GetRefreshThresholdRate returns the refresh threshold rate, which defaults to 1/2
+func (c ProverConfig) GetRefreshThresholdRate() Fraction {
+	if c.RefreshThresholdRate == nil {
+		return Fraction{Numerator: 1, Denominator: 2}
 	}
-	d, err := time.ParseDuration(c.MaxClockDrift)
-	if err != nil {
-		panic(err)
+	return *c.RefreshThresholdRate
+}
+
+// GetIBCCommitmentsSlot returns the storage slot of the commitments mapping in the IBC contract
+func (c ProverConfig) GetIBCCommitmentsSlot() (common.Hash, error) {
+	switch {
+	case c.IbcCommitmentsSlot != "":
+		// the slot is accepted with or without the 0x prefix, as IBCCommitmentsSlot is written without it
+		bz, err := hex.DecodeString(strings.TrimPrefix(c.IbcCommitmentsSlot, "0x"))
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("invalid ibc commitments slot: %s: must be hex with or without the 0x prefix: %v", c.IbcCommitmentsSlot, err)
+		} else if len(bz) != common.HashLength {
+			return common.Hash{}, fmt.Errorf("ibc commitments slot must be %v bytes: %s", common.HashLength, c.IbcCommitmentsSlot)
+		}
+		return common.BytesToHash(bz), nil
+	case c.IbcCommitmentsNamespace != "":
+		return erc7201Slot(c.IbcCommitmentsNamespace), nil
+	default:
+		return IBCCommitmentsSlot, nil
 	}
-	return d
+}
+
+// erc7201Slot returns the storage slot of the ERC-7201 namespace,
+// which is keccak256(abi.encode(uint256(keccak256(namespace)) - 1)) & ~bytes32(uint256(0xff))
+func erc7201Slot(namespace string) common.Hash {
+	n := new(big.Int).SetBytes(crypto.Keccak256([]byte(namespace)))
+	n.Sub(n, big.NewInt(1))
+	var bz [32]byte
+	n.FillBytes(bz[:])
+	slot := crypto.Keccak256Hash(bz[:])
+	slot[common.HashLength-1] = 0
+	return slot
 }
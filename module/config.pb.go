@@ -7,15 +7,19 @@ import (
 	fmt "fmt"
 	_ "github.com/cosmos/gogoproto/gogoproto"
 	proto "github.com/cosmos/gogoproto/proto"
+	github_com_cosmos_gogoproto_types "github.com/cosmos/gogoproto/types"
+	_ "google.golang.org/protobuf/types/known/durationpb"
 	io "io"
 	math "math"
 	math_bits "math/bits"
+	time "time"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
+var _ = time.Kitchen
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the proto package it is being compiled against.
@@ -24,9 +28,73 @@ var _ = math.Inf
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type ProverConfig struct {
-	ConsensusType  string `protobuf:"bytes,1,opt,name=consensus_type,json=consensusType,proto3" json:"consensus_type,omitempty"`
+	// consensus type of the chain from the genesis, which is either "qbft" (default) or "ibft2"
+	ConsensusType string `protobuf:"bytes,1,opt,name=consensus_type,json=consensusType,proto3" json:"consensus_type,omitempty"`
+	// deprecated: use trusting_period_duration
+	// Go duration string, e.g. "336h"
 	TrustingPeriod string `protobuf:"bytes,2,opt,name=trusting_period,json=trustingPeriod,proto3" json:"trusting_period,omitempty"`
-	MaxClockDrift  string `protobuf:"bytes,3,opt,name=max_clock_drift,json=maxClockDrift,proto3" json:"max_clock_drift,omitempty"`
+	// deprecated: use max_clock_drift_duration
+	// Go duration string, e.g. "10s"
+	MaxClockDrift string `protobuf:"bytes,3,opt,name=max_clock_drift,json=maxClockDrift,proto3" json:"max_clock_drift,omitempty"`
+	// the client is refreshed when the time elapsed since its latest consensus state exceeds trusting_period * refresh_threshold_rate
+	// if this is not set, 1/2 is used
+	RefreshThresholdRate *Fraction `protobuf:"bytes,4,opt,name=refresh_threshold_rate,json=refreshThresholdRate,proto3" json:"refresh_threshold_rate,omitempty"`
+	// hex-encoded storage slot of the commitments mapping in the IBC contract, with or without the 0x prefix
+	// this cannot be set together with ibc_commitments_namespace
+	IbcCommitmentsSlot string `protobuf:"bytes,5,opt,name=ibc_commitments_slot,json=ibcCommitmentsSlot,proto3" json:"ibc_commitments_slot,omitempty"`
+	// ERC-7201 namespace of the commitments mapping in the IBC contract
+	// the slot is calculated as keccak256(abi.encode(uint256(keccak256(namespace)) - 1)) & ~bytes32(uint256(0xff))
+	// if neither this nor ibc_commitments_slot is set, the slot of yui-ibc-solidity is used
+	IbcCommitmentsNamespace string `protobuf:"bytes,6,opt,name=ibc_commitments_namespace,json=ibcCommitmentsNamespace,proto3" json:"ibc_commitments_namespace,omitempty"`
+	// revision number of the heights of the chain
+	// this must be incremented when the chain is restarted from the genesis, and the clients must be upgraded accordingly
+	RevisionNumber uint64 `protobuf:"varint,7,opt,name=revision_number,json=revisionNumber,proto3" json:"revision_number,omitempty"`
+	// hex-encoded address of the validator contract
+	// if this is set, the validator set of each block is read from the contract instead of the extra data
+	// the headers carry the proofs of the validator sets, which QBFTClient.sol does not support,
+	// so the counterparty must be a chain with the light client of this module
+	ValidatorContractAddress string `protobuf:"bytes,8,opt,name=validator_contract_address,json=validatorContractAddress,proto3" json:"validator_contract_address,omitempty"`
+	// storage slot of the validator array in the validator contract, which requires validator_contract_address
+	ValidatorContractSlot uint64 `protobuf:"varint,9,opt,name=validator_contract_slot,json=validatorContractSlot,proto3" json:"validator_contract_slot,omitempty"`
+	// consensus types switched at the given blocks, e.g. the migration from IBFT 2.0 to QBFT
+	// the blocks must be in ascending order
+	ConsensusForks []*ConsensusFork `protobuf:"bytes,10,rep,name=consensus_forks,json=consensusForks,proto3" json:"consensus_forks,omitempty"`
+	// additional RPC endpoints of the chain to cross-check the headers, proofs and call results with the one of the chain config
+	// the prover refuses the data if the responding endpoints disagree
+	RpcAddrs []string `protobuf:"bytes,11,rep,name=rpc_addrs,json=rpcAddrs,proto3" json:"rpc_addrs,omitempty"`
+	// minimum number of the endpoints that must respond, including the one of the chain config
+	// if this is not set, 1 is used, i.e. the prover works as long as any endpoint is healthy
+	RpcQuorum uint32 `protobuf:"varint,12,opt,name=rpc_quorum,json=rpcQuorum,proto3" json:"rpc_quorum,omitempty"`
+	// number of blocks the latest finalized header must be behind the head
+	ConfirmationDepth uint64 `protobuf:"varint,13,opt,name=confirmation_depth,json=confirmationDepth,proto3" json:"confirmation_depth,omitempty"`
+	// block tag of the head, which is one of "latest" (default), "safe" and "finalized"
+	BlockTag string `protobuf:"bytes,14,opt,name=block_tag,json=blockTag,proto3" json:"block_tag,omitempty"`
+	// number of blocks whose headers are cached in memory
+	// if this is not set, 128 is used
+	HeaderCacheSize uint32 `protobuf:"varint,15,opt,name=header_cache_size,json=headerCacheSize,proto3" json:"header_cache_size,omitempty"`
+	// if this is true, the headers are also cached under the home directory to survive restarts
+	// the number of the cached blocks is bounded by header_disk_cache_size
+	// the headers are cached separately for each set of the settings they depend on, such as revision_number,
+	// the consensus types and the validator contract, so a change of them never reuses the headers built before
+	HeaderDiskCache bool `protobuf:"varint,16,opt,name=header_disk_cache,json=headerDiskCache,proto3" json:"header_disk_cache,omitempty"`
+	// number of the latest blocks over which the seal participation of the validators is tracked
+	// the window is counted in block numbers from the latest recorded block, not in the number of the recorded blocks
+	// if this is not set, 1000 is used
+	LivenessWindow uint32 `protobuf:"varint,17,opt,name=liveness_window,json=livenessWindow,proto3" json:"liveness_window,omitempty"`
+	// trusting period of the client, which must be a positive whole number of seconds
+	// this cannot be set together with trusting_period
+	TrustingPeriodDuration *time.Duration `protobuf:"bytes,18,opt,name=trusting_period_duration,json=trustingPeriodDuration,proto3,stdduration" json:"trusting_period_duration,omitempty"`
+	// max clock drift of the client, which must be a whole number of seconds less than the trusting period
+	// this cannot be set together with max_clock_drift
+	MaxClockDriftDuration *time.Duration `protobuf:"bytes,19,opt,name=max_clock_drift_duration,json=maxClockDriftDuration,proto3,stdduration" json:"max_clock_drift_duration,omitempty"`
+	// maximum number of blocks searched for the validator set changes between the trusted height and the target height
+	// if the target is further away, the update fails instead of querying every block in between
+	// if this is not set, 10000 is used
+	MaxTransitionSearchRange uint64 `protobuf:"varint,20,opt,name=max_transition_search_range,json=maxTransitionSearchRange,proto3" json:"max_transition_search_range,omitempty"`
+	// number of the latest blocks whose headers are kept in the disk cache if header_disk_cache is true
+	// the headers of the older blocks are removed from the cache directory as newer headers are cached
+	// if this is not set, 1024 is used
+	HeaderDiskCacheSize uint64 `protobuf:"varint,21,opt,name=header_disk_cache_size,json=headerDiskCacheSize,proto3" json:"header_disk_cache_size,omitempty"`
 }
 
 func (m *ProverConfig) Reset()         { *m = ProverConfig{} }
@@ -62,8 +130,87 @@ func (m *ProverConfig) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ProverConfig proto.InternalMessageInfo
 
+type ConsensusFork struct {
+	// the first block of the consensus type
+	Block         uint64 `protobuf:"varint,1,opt,name=block,proto3" json:"block,omitempty"`
+	ConsensusType string `protobuf:"bytes,2,opt,name=consensus_type,json=consensusType,proto3" json:"consensus_type,omitempty"`
+}
+
+func (m *ConsensusFork) Reset()         { *m = ConsensusFork{} }
+func (m *ConsensusFork) String() string { return proto.CompactTextString(m) }
+func (*ConsensusFork) ProtoMessage()    {}
+func (*ConsensusFork) Descriptor() ([]byte, []int) {
+	return fileDescriptor_31b3e6aa48d48dba, []int{1}
+}
+func (m *ConsensusFork) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ConsensusFork) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ConsensusFork.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ConsensusFork) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConsensusFork.Merge(m, src)
+}
+func (m *ConsensusFork) XXX_Size() int {
+	return m.Size()
+}
+func (m *ConsensusFork) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConsensusFork.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConsensusFork proto.InternalMessageInfo
+
+type Fraction struct {
+	Numerator   uint64 `protobuf:"varint,1,opt,name=numerator,proto3" json:"numerator,omitempty"`
+	Denominator uint64 `protobuf:"varint,2,opt,name=denominator,proto3" json:"denominator,omitempty"`
+}
+
+func (m *Fraction) Reset()         { *m = Fraction{} }
+func (m *Fraction) String() string { return proto.CompactTextString(m) }
+func (*Fraction) ProtoMessage()    {}
+func (*Fraction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_31b3e6aa48d48dba, []int{2}
+}
+func (m *Fraction) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Fraction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Fraction.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Fraction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Fraction.Merge(m, src)
+}
+func (m *Fraction) XXX_Size() int {
+	return m.Size()
+}
+func (m *Fraction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Fraction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Fraction proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*ProverConfig)(nil), "relayer.provers.qbft.config.ProverConfig")
+	proto.RegisterType((*ConsensusFork)(nil), "relayer.provers.qbft.config.ConsensusFork")
+	proto.RegisterType((*Fraction)(nil), "relayer.provers.qbft.config.Fraction")
 }
 
 func init() {
@@ -71,24 +218,58 @@ func init() {
 }
 
 var fileDescriptor_31b3e6aa48d48dba = []byte{
-	// 267 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x3c, 0xcf, 0x4d, 0x4a, 0xc4, 0x30,
-	0x18, 0xc6, 0xf1, 0x46, 0x41, 0xb0, 0xe8, 0x0c, 0x14, 0x17, 0x45, 0x21, 0x88, 0xa0, 0xce, 0xa6,
-	0xcd, 0x42, 0x4f, 0xe0, 0x78, 0x80, 0x61, 0x70, 0xe5, 0xa6, 0x24, 0x69, 0xda, 0x09, 0xb6, 0x7d,
-	0x63, 0x3e, 0x64, 0x7a, 0x01, 0xd7, 0x1e, 0x6b, 0x96, 0xb3, 0x74, 0xa9, 0xed, 0x45, 0xa4, 0x69,
-	0x9d, 0x55, 0xc2, 0x9f, 0x5f, 0x02, 0x4f, 0xb8, 0xd0, 0xa2, 0xa2, 0xad, 0xd0, 0x44, 0x69, 0xf8,
-	0x10, 0xda, 0x90, 0x77, 0x56, 0x58, 0xc2, 0xa1, 0x29, 0x64, 0x39, 0x1d, 0xa9, 0xd2, 0x60, 0x21,
-	0xba, 0x9a, 0x64, 0x3a, 0xc9, 0x74, 0x90, 0xe9, 0x48, 0x2e, 0x2f, 0x4a, 0x28, 0xc1, 0x3b, 0x32,
-	0xdc, 0xc6, 0x27, 0x37, 0x9f, 0x28, 0x3c, 0x5b, 0x79, 0xbd, 0xf4, 0x2c, 0xba, 0x0d, 0x67, 0x1c,
-	0x1a, 0x23, 0x1a, 0xe3, 0x4c, 0x66, 0x5b, 0x25, 0x62, 0x74, 0x8d, 0x16, 0xa7, 0xeb, 0xf3, 0x43,
-	0x7d, 0x69, 0x95, 0x88, 0xee, 0xc3, 0xb9, 0xd5, 0xce, 0x58, 0xd9, 0x94, 0x99, 0x12, 0x5a, 0x42,
-	0x1e, 0x1f, 0x79, 0x37, 0xfb, 0xcf, 0x2b, 0x5f, 0xa3, 0xbb, 0x70, 0x5e, 0xd3, 0x6d, 0xc6, 0x2b,
-	0xe0, 0x6f, 0x59, 0xae, 0x65, 0x61, 0xe3, 0xe3, 0xf1, 0xc3, 0x9a, 0x6e, 0x97, 0x43, 0x7d, 0x1e,
-	0xe2, 0xd3, 0x7a, 0xf7, 0x8b, 0x83, 0x5d, 0x87, 0xd1, 0xbe, 0xc3, 0xe8, 0xa7, 0xc3, 0xe8, 0xab,
-	0xc7, 0xc1, 0xbe, 0xc7, 0xc1, 0x77, 0x8f, 0x83, 0xd7, 0xc7, 0x52, 0xda, 0x8d, 0x63, 0x29, 0x87,
-	0x9a, 0xe4, 0xd4, 0x52, 0xbe, 0xa1, 0xb2, 0xa9, 0x28, 0x23, 0x4c, 0x18, 0x97, 0x48, 0xc6, 0x13,
-	0x3f, 0x3d, 0x19, 0x87, 0x93, 0x1a, 0x72, 0x57, 0x09, 0x76, 0xe2, 0x37, 0x3e, 0xfc, 0x05, 0x00,
-	0x00, 0xff, 0xff, 0x83, 0x5f, 0x42, 0x63, 0x42, 0x01, 0x00, 0x00,
+	// 802 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x4d, 0x6f, 0xdc, 0x36,
+	0x10, 0xb5, 0x6c, 0x27, 0xdd, 0xa5, 0xe3, 0xdd, 0x9a, 0x59, 0x3b, 0x8c, 0xdd, 0x6e, 0x17, 0x06,
+	0xd2, 0x0a, 0x01, 0x2c, 0x15, 0x49, 0xd1, 0x43, 0xd1, 0x1e, 0x9a, 0x35, 0x72, 0x28, 0x8a, 0x20,
+	0x95, 0x0d, 0xf4, 0xeb, 0x40, 0x50, 0x14, 0x57, 0x22, 0x56, 0x22, 0x15, 0x92, 0x72, 0xe2, 0xfc,
+	0x8a, 0x1e, 0xfb, 0x93, 0x72, 0xcc, 0xb1, 0xb7, 0xb6, 0xf6, 0x8f, 0xe8, 0xb5, 0xe0, 0x68, 0xe5,
+	0x8f, 0x8d, 0x11, 0xe4, 0xb4, 0xab, 0xf7, 0xde, 0xbc, 0xe1, 0xcc, 0x70, 0x88, 0x42, 0x23, 0x4a,
+	0x76, 0x2a, 0x4c, 0x5c, 0x1b, 0x7d, 0x22, 0x8c, 0x8d, 0x5f, 0xa4, 0x33, 0x17, 0x73, 0xad, 0x66,
+	0x32, 0x5f, 0xfc, 0x44, 0xb5, 0xd1, 0x4e, 0xe3, 0xbd, 0x85, 0x32, 0x5a, 0x28, 0x23, 0xaf, 0x8c,
+	0x5a, 0xc9, 0xee, 0x28, 0xd7, 0xb9, 0x06, 0x5d, 0xec, 0xff, 0xb5, 0x21, 0xbb, 0xe3, 0x5c, 0xeb,
+	0xbc, 0x14, 0x31, 0x7c, 0xa5, 0xcd, 0x2c, 0xce, 0x1a, 0xc3, 0x9c, 0xd4, 0xaa, 0xe5, 0xf7, 0xff,
+	0xeb, 0xa1, 0x3b, 0xcf, 0xc1, 0x6d, 0x0a, 0x36, 0xf8, 0x01, 0x1a, 0x70, 0xad, 0xac, 0x50, 0xb6,
+	0xb1, 0xd4, 0x9d, 0xd6, 0x82, 0x04, 0x93, 0x20, 0xec, 0x27, 0x9b, 0x17, 0xe8, 0xf1, 0x69, 0x2d,
+	0xf0, 0x17, 0x68, 0xe8, 0x4c, 0x63, 0x9d, 0x54, 0x39, 0xad, 0x85, 0x91, 0x3a, 0x23, 0xab, 0xa0,
+	0x1b, 0x74, 0xf0, 0x73, 0x40, 0xf1, 0xe7, 0x68, 0x58, 0xb1, 0x57, 0x94, 0x97, 0x9a, 0xcf, 0x69,
+	0x66, 0xe4, 0xcc, 0x91, 0xb5, 0xd6, 0xb0, 0x62, 0xaf, 0xa6, 0x1e, 0x3d, 0xf4, 0x20, 0xfe, 0x1d,
+	0xed, 0x18, 0x31, 0x33, 0xc2, 0x16, 0xd4, 0x15, 0xfe, 0x47, 0x97, 0x19, 0x35, 0xcc, 0x09, 0xb2,
+	0x3e, 0x09, 0xc2, 0x8d, 0x47, 0x0f, 0xa2, 0xf7, 0x14, 0x1f, 0x3d, 0x35, 0x8c, 0xfb, 0xaa, 0x92,
+	0xd1, 0xc2, 0xe4, 0xb8, 0xf3, 0x48, 0x98, 0x13, 0xf8, 0x4b, 0x34, 0x92, 0x29, 0xa7, 0x5c, 0x57,
+	0x95, 0x74, 0x95, 0x50, 0xce, 0x52, 0x5b, 0x6a, 0x47, 0x6e, 0xc1, 0x49, 0xb0, 0x4c, 0xf9, 0xf4,
+	0x92, 0x3a, 0x2a, 0xb5, 0xc3, 0xdf, 0xa0, 0xfb, 0xcb, 0x11, 0x8a, 0x55, 0xc2, 0xd6, 0x8c, 0x0b,
+	0x72, 0x1b, 0xc2, 0xee, 0x5d, 0x0f, 0x7b, 0xd6, 0xd1, 0xbe, 0x37, 0x46, 0x9c, 0x48, 0x2b, 0xb5,
+	0xa2, 0xaa, 0xa9, 0x52, 0x61, 0xc8, 0x47, 0x93, 0x20, 0x5c, 0x4f, 0x06, 0x1d, 0xfc, 0x0c, 0x50,
+	0xfc, 0x2d, 0xda, 0x3d, 0x61, 0xa5, 0xcc, 0x98, 0xd3, 0x86, 0x72, 0xad, 0x9c, 0xaf, 0x82, 0xb2,
+	0x2c, 0x33, 0xc2, 0x5a, 0xd2, 0x83, 0x2c, 0xe4, 0x42, 0x31, 0x5d, 0x08, 0xbe, 0x6f, 0x79, 0xfc,
+	0x35, 0xba, 0x77, 0x43, 0x34, 0xd4, 0xd5, 0x87, 0x74, 0xdb, 0xef, 0x84, 0x42, 0x69, 0x47, 0x68,
+	0x78, 0x39, 0xe1, 0x99, 0x36, 0x73, 0x4b, 0xd0, 0x64, 0x2d, 0xdc, 0x78, 0xf4, 0xf0, 0xbd, 0x2d,
+	0x9e, 0x76, 0x31, 0x4f, 0xb5, 0x99, 0x27, 0x03, 0x7e, 0xf5, 0xd3, 0xe2, 0x3d, 0xd4, 0x37, 0x35,
+	0x87, 0xb3, 0x5b, 0xb2, 0x31, 0x59, 0x0b, 0xfb, 0x49, 0xcf, 0xd4, 0xdc, 0x9f, 0xd5, 0xe2, 0x4f,
+	0x11, 0xf2, 0xe4, 0x8b, 0x46, 0x9b, 0xa6, 0x22, 0x77, 0x26, 0x41, 0xb8, 0x99, 0x78, 0xf9, 0x4f,
+	0x00, 0xe0, 0x03, 0x84, 0x21, 0x87, 0xa9, 0xe0, 0x66, 0xd2, 0x4c, 0xd4, 0xae, 0x20, 0x9b, 0x50,
+	0xc3, 0xd6, 0x55, 0xe6, 0xd0, 0x13, 0x3e, 0x55, 0x0a, 0xb7, 0xc9, 0xb1, 0x9c, 0x0c, 0xa0, 0x49,
+	0x3d, 0x00, 0x8e, 0x59, 0x8e, 0x1f, 0xa2, 0xad, 0x42, 0xb0, 0x4c, 0x18, 0xca, 0x19, 0x2f, 0x04,
+	0xb5, 0xf2, 0xb5, 0x20, 0x43, 0xc8, 0x38, 0x6c, 0x89, 0xa9, 0xc7, 0x8f, 0xe4, 0x6b, 0x71, 0x45,
+	0x9b, 0x49, 0x3b, 0x6f, 0x03, 0xc8, 0xc7, 0x93, 0x20, 0xec, 0x75, 0xda, 0x43, 0x69, 0xe7, 0xa0,
+	0xf7, 0x33, 0x2d, 0xe5, 0x89, 0x50, 0xc2, 0x5a, 0xfa, 0x52, 0xaa, 0x4c, 0xbf, 0x24, 0x5b, 0xe0,
+	0x3a, 0xe8, 0xe0, 0x9f, 0x01, 0xc5, 0xbf, 0x22, 0xb2, 0xb4, 0x18, 0xb4, 0x5b, 0x39, 0x82, 0xe1,
+	0x26, 0xdf, 0x8f, 0xda, 0x9d, 0x8c, 0xba, 0x9d, 0x8c, 0x0e, 0x17, 0x82, 0x27, 0xeb, 0x7f, 0xfe,
+	0xfd, 0x59, 0x90, 0xec, 0x5c, 0x5f, 0xa1, 0x8e, 0xc5, 0xbf, 0x20, 0xb2, 0xb4, 0x4a, 0x97, 0xd6,
+	0x77, 0x3f, 0xcc, 0x7a, 0xfb, 0xda, 0xd2, 0x5d, 0x38, 0x7f, 0x87, 0xf6, 0xbc, 0xb3, 0x33, 0x4c,
+	0x59, 0x09, 0x33, 0xb0, 0x82, 0x19, 0x5e, 0x50, 0xc3, 0x54, 0x2e, 0xc8, 0x08, 0x46, 0xe1, 0x93,
+	0x1f, 0x5f, 0x28, 0x8e, 0x40, 0x90, 0x78, 0x1e, 0x3f, 0x46, 0x3b, 0xef, 0x34, 0xb2, 0xed, 0xfc,
+	0x36, 0x44, 0xde, 0x5d, 0xea, 0xa6, 0xef, 0xfe, 0xfe, 0x8f, 0x68, 0xf3, 0xda, 0x95, 0xc2, 0x23,
+	0x74, 0x0b, 0xc6, 0x08, 0x0f, 0xce, 0x7a, 0xd2, 0x7e, 0xdc, 0xf0, 0x1e, 0xad, 0xde, 0xf0, 0x1e,
+	0xed, 0xff, 0x80, 0x7a, 0xdd, 0x1b, 0x80, 0x3f, 0x41, 0x7d, 0xd5, 0x54, 0xc2, 0xf8, 0x9b, 0xbf,
+	0x30, 0xbb, 0x04, 0xf0, 0x04, 0x6d, 0x64, 0x42, 0xe9, 0x4a, 0x2a, 0xe0, 0x57, 0x81, 0xbf, 0x0a,
+	0x3d, 0x49, 0xde, 0xfc, 0x3b, 0x5e, 0x79, 0x73, 0x36, 0x0e, 0xde, 0x9e, 0x8d, 0x83, 0x7f, 0xce,
+	0xc6, 0xc1, 0x1f, 0xe7, 0xe3, 0x95, 0xb7, 0xe7, 0xe3, 0x95, 0xbf, 0xce, 0xc7, 0x2b, 0xbf, 0x7d,
+	0x95, 0x4b, 0x57, 0x34, 0x69, 0xc4, 0x75, 0x15, 0x67, 0xcc, 0x31, 0x5e, 0x30, 0xa9, 0x4a, 0x96,
+	0xc6, 0xa9, 0xb0, 0xcd, 0x81, 0x4c, 0xf9, 0x01, 0x6c, 0xd1, 0x41, 0xbb, 0x43, 0x71, 0xa5, 0xb3,
+	0xa6, 0x14, 0xe9, 0x6d, 0x98, 0xc8, 0xe3, 0xff, 0x07, 0x00, 0x2b, 0xa4, 0x6f, 0x5d, 0xed, 0x05,
+	0x00, 0x00,
 }
 
 func (m *ProverConfig) Marshal() (dAtA []byte, err error) {
@@ -111,6 +292,151 @@ func (m *ProverConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.HeaderDiskCacheSize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.HeaderDiskCacheSize))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa8
+	}
+	if m.MaxTransitionSearchRange != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxTransitionSearchRange))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa0
+	}
+	if m.MaxClockDriftDuration != nil {
+		n1, err1 := github_com_cosmos_gogoproto_types.StdDurationMarshalTo(*m.MaxClockDriftDuration, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdDuration(*m.MaxClockDriftDuration):])
+		if err1 != nil {
+			return 0, err1
+		}
+		i -= n1
+		i = encodeVarintConfig(dAtA, i, uint64(n1))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x9a
+	}
+	if m.TrustingPeriodDuration != nil {
+		n2, err2 := github_com_cosmos_gogoproto_types.StdDurationMarshalTo(*m.TrustingPeriodDuration, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdDuration(*m.TrustingPeriodDuration):])
+		if err2 != nil {
+			return 0, err2
+		}
+		i -= n2
+		i = encodeVarintConfig(dAtA, i, uint64(n2))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
+	}
+	if m.LivenessWindow != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.LivenessWindow))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
+	}
+	if m.HeaderDiskCache {
+		i--
+		if m.HeaderDiskCache {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
+	if m.HeaderCacheSize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.HeaderCacheSize))
+		i--
+		dAtA[i] = 0x78
+	}
+	if len(m.BlockTag) > 0 {
+		i -= len(m.BlockTag)
+		copy(dAtA[i:], m.BlockTag)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.BlockTag)))
+		i--
+		dAtA[i] = 0x72
+	}
+	if m.ConfirmationDepth != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ConfirmationDepth))
+		i--
+		dAtA[i] = 0x68
+	}
+	if m.RpcQuorum != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.RpcQuorum))
+		i--
+		dAtA[i] = 0x60
+	}
+	if len(m.RpcAddrs) > 0 {
+		for iNdEx := len(m.RpcAddrs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.RpcAddrs[iNdEx])
+			copy(dAtA[i:], m.RpcAddrs[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.RpcAddrs[iNdEx])))
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if len(m.ConsensusForks) > 0 {
+		for iNdEx := len(m.ConsensusForks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ConsensusForks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintConfig(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if m.ValidatorContractSlot != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ValidatorContractSlot))
+		i--
+		dAtA[i] = 0x48
+	}
+	if len(m.ValidatorContractAddress) > 0 {
+		i -= len(m.ValidatorContractAddress)
+		copy(dAtA[i:], m.ValidatorContractAddress)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ValidatorContractAddress)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.RevisionNumber != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.RevisionNumber))
+		i--
+		dAtA[i] = 0x38
+	}
+	if len(m.IbcCommitmentsNamespace) > 0 {
+		i -= len(m.IbcCommitmentsNamespace)
+		copy(dAtA[i:], m.IbcCommitmentsNamespace)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.IbcCommitmentsNamespace)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.IbcCommitmentsSlot) > 0 {
+		i -= len(m.IbcCommitmentsSlot)
+		copy(dAtA[i:], m.IbcCommitmentsSlot)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.IbcCommitmentsSlot)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.RefreshThresholdRate != nil {
+		{
+			size, err := m.RefreshThresholdRate.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
 	if len(m.MaxClockDrift) > 0 {
 		i -= len(m.MaxClockDrift)
 		copy(dAtA[i:], m.MaxClockDrift)
@@ -135,6 +461,74 @@ func (m *ProverConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ConsensusFork) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConsensusFork) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConsensusFork) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ConsensusType) > 0 {
+		i -= len(m.ConsensusType)
+		copy(dAtA[i:], m.ConsensusType)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ConsensusType)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Block != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.Block))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Fraction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Fraction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Fraction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Denominator != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.Denominator))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Numerator != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.Numerator))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
 	offset -= sovConfig(v)
 	base := offset
@@ -164,6 +558,104 @@ func (m *ProverConfig) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	if m.RefreshThresholdRate != nil {
+		l = m.RefreshThresholdRate.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.IbcCommitmentsSlot)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.IbcCommitmentsNamespace)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.RevisionNumber != 0 {
+		n += 1 + sovConfig(uint64(m.RevisionNumber))
+	}
+	l = len(m.ValidatorContractAddress)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.ValidatorContractSlot != 0 {
+		n += 1 + sovConfig(uint64(m.ValidatorContractSlot))
+	}
+	if len(m.ConsensusForks) > 0 {
+		for _, e := range m.ConsensusForks {
+			l = e.Size()
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.RpcAddrs) > 0 {
+		for _, s := range m.RpcAddrs {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.RpcQuorum != 0 {
+		n += 1 + sovConfig(uint64(m.RpcQuorum))
+	}
+	if m.ConfirmationDepth != 0 {
+		n += 1 + sovConfig(uint64(m.ConfirmationDepth))
+	}
+	l = len(m.BlockTag)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.HeaderCacheSize != 0 {
+		n += 1 + sovConfig(uint64(m.HeaderCacheSize))
+	}
+	if m.HeaderDiskCache {
+		n += 3
+	}
+	if m.LivenessWindow != 0 {
+		n += 2 + sovConfig(uint64(m.LivenessWindow))
+	}
+	if m.TrustingPeriodDuration != nil {
+		l = github_com_cosmos_gogoproto_types.SizeOfStdDuration(*m.TrustingPeriodDuration)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.MaxClockDriftDuration != nil {
+		l = github_com_cosmos_gogoproto_types.SizeOfStdDuration(*m.MaxClockDriftDuration)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.MaxTransitionSearchRange != 0 {
+		n += 2 + sovConfig(uint64(m.MaxTransitionSearchRange))
+	}
+	if m.HeaderDiskCacheSize != 0 {
+		n += 2 + sovConfig(uint64(m.HeaderDiskCacheSize))
+	}
+	return n
+}
+
+func (m *ConsensusFork) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Block != 0 {
+		n += 1 + sovConfig(uint64(m.Block))
+	}
+	l = len(m.ConsensusType)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Fraction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Numerator != 0 {
+		n += 1 + sovConfig(uint64(m.Numerator))
+	}
+	if m.Denominator != 0 {
+		n += 1 + sovConfig(uint64(m.Denominator))
+	}
 	return n
 }
 
@@ -298,6 +790,669 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 			}
 			m.MaxClockDrift = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RefreshThresholdRate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RefreshThresholdRate == nil {
+				m.RefreshThresholdRate = &Fraction{}
+			}
+			if err := m.RefreshThresholdRate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IbcCommitmentsSlot", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IbcCommitmentsSlot = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IbcCommitmentsNamespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IbcCommitmentsNamespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RevisionNumber", wireType)
+			}
+			m.RevisionNumber = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RevisionNumber |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorContractAddress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ValidatorContractAddress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorContractSlot", wireType)
+			}
+			m.ValidatorContractSlot = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ValidatorContractSlot |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsensusForks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConsensusForks = append(m.ConsensusForks, &ConsensusFork{})
+			if err := m.ConsensusForks[len(m.ConsensusForks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RpcAddrs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RpcAddrs = append(m.RpcAddrs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RpcQuorum", wireType)
+			}
+			m.RpcQuorum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RpcQuorum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConfirmationDepth", wireType)
+			}
+			m.ConfirmationDepth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ConfirmationDepth |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderCacheSize", wireType)
+			}
+			m.HeaderCacheSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeaderCacheSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderDiskCache", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HeaderDiskCache = bool(v != 0)
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LivenessWindow", wireType)
+			}
+			m.LivenessWindow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LivenessWindow |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrustingPeriodDuration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TrustingPeriodDuration == nil {
+				m.TrustingPeriodDuration = new(time.Duration)
+			}
+			if err := github_com_cosmos_gogoproto_types.StdDurationUnmarshal(m.TrustingPeriodDuration, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxClockDriftDuration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxClockDriftDuration == nil {
+				m.MaxClockDriftDuration = new(time.Duration)
+			}
+			if err := github_com_cosmos_gogoproto_types.StdDurationUnmarshal(m.MaxClockDriftDuration, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxTransitionSearchRange", wireType)
+			}
+			m.MaxTransitionSearchRange = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxTransitionSearchRange |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 21:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderDiskCacheSize", wireType)
+			}
+			m.HeaderDiskCacheSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeaderDiskCacheSize |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConsensusFork) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConsensusFork: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConsensusFork: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Block", wireType)
+			}
+			m.Block = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Block |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsensusType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConsensusType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Fraction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Fraction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Fraction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Numerator", wireType)
+			}
+			m.Numerator = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Numerator |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Denominator", wireType)
+			}
+			m.Denominator = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Denominator |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
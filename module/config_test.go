@@ -0,0 +1,197 @@
+package module
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/relay/ethereum"
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/relay/ethereum/signers/hd"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+func TestProverConfigValidate(t *testing.T) {
+	trustingPeriod := 14 * 24 * time.Hour
+	maxClockDrift := 30 * time.Second
+	valid := func() ProverConfig {
+		return ProverConfig{TrustingPeriodDuration: &trustingPeriod, MaxClockDriftDuration: &maxClockDrift}
+	}
+	for _, c := range []struct {
+		name   string
+		config func() ProverConfig
+		// errors are the substrings of the errors joined by Validate, in order
+		errors []string
+	}{
+		{name: "valid", config: valid},
+		{name: "deprecated strings", config: func() ProverConfig {
+			return ProverConfig{TrustingPeriod: "336h", MaxClockDrift: "30s"}
+		}},
+		{name: "no trusting period", config: func() ProverConfig {
+			return ProverConfig{MaxClockDriftDuration: &maxClockDrift}
+		}, errors: []string{`"trusting_period_duration" must be positive`}},
+		{name: "zero trusting period", config: func() ProverConfig {
+			zero := time.Duration(0)
+			return ProverConfig{TrustingPeriodDuration: &zero}
+		}, errors: []string{`"trusting_period_duration" must be positive`}},
+		{name: "both forms", config: func() ProverConfig {
+			c := valid()
+			c.TrustingPeriod = "336h"
+			return c
+		}, errors: []string{`"trusting_period_duration" and "trusting_period" cannot be set at the same time`}},
+		{name: "invalid string", config: func() ProverConfig {
+			return ProverConfig{TrustingPeriod: "two weeks"}
+		}, errors: []string{"invalid trusting period: two weeks"}},
+		{name: "fractional seconds", config: func() ProverConfig {
+			c := valid()
+			d := 1500 * time.Millisecond
+			c.MaxClockDriftDuration = &d
+			return c
+		}, errors: []string{`"max_clock_drift" must be a whole number of seconds`}},
+		{name: "drift not less than trusting period", config: func() ProverConfig {
+			c := valid()
+			c.MaxClockDriftDuration = &trustingPeriod
+			return c
+		}, errors: []string{"max clock drift must be less than the trusting period"}},
+		{name: "validator contract", config: func() ProverConfig {
+			c := valid()
+			c.ValidatorContractAddress = "0x0000000000000000000000000000000000008888"
+			c.ValidatorContractSlot = 3
+			return c
+		}},
+		{name: "zero validator contract address", config: func() ProverConfig {
+			c := valid()
+			c.ValidatorContractAddress = "0x0000000000000000000000000000000000000000"
+			return c
+		}, errors: []string{`"validator_contract_address" must not be the zero address`}},
+		{name: "validator contract slot without address", config: func() ProverConfig {
+			c := valid()
+			c.ValidatorContractSlot = 3
+			return c
+		}, errors: []string{`"validator_contract_slot" cannot be set without "validator_contract_address"`}},
+		{name: "validator contract with ibft2", config: func() ProverConfig {
+			c := valid()
+			c.ConsensusType = IBFT2ConsensusType
+			c.ConsensusForks = []*ConsensusFork{{Block: 10, ConsensusType: QBFTConsensusType}, {Block: 20, ConsensusType: IBFT2ConsensusType}}
+			c.ValidatorContractAddress = "0x0000000000000000000000000000000000008888"
+			return c
+		}, errors: []string{
+			"the validator contract cannot be used with ibft2",
+			"the validator contract cannot be used with ibft2 of consensus_forks[1]",
+		}},
+		{name: "all errors", config: func() ProverConfig {
+			c := valid()
+			c.TrustingPeriodDuration = nil
+			c.ConsensusType = "pos"
+			c.RefreshThresholdRate = &Fraction{Numerator: 2, Denominator: 1}
+			c.BlockTag = "pending"
+			return c
+		}, errors: []string{
+			"invalid consensus type: pos",
+			`"trusting_period_duration" must be positive`,
+			`"refresh_threshold_rate" must be less than or equal to 1.0`,
+			"invalid block tag: pending",
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config().Validate()
+			if len(c.errors) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			joined, ok := err.(interface{ Unwrap() []error })
+			if !ok {
+				t.Fatalf("errors are not joined: %v", err)
+			}
+			errs := joined.Unwrap()
+			if len(errs) != len(c.errors) {
+				t.Fatalf("unexpected number of errors: expected=%v actual=%v", c.errors, errs)
+			}
+			for i, expected := range c.errors {
+				if !strings.Contains(errs[i].Error(), expected) {
+					t.Fatalf("unexpected error %v: expected=%q actual=%q", i, expected, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProverConfigDurations(t *testing.T) {
+	// the deprecated strings are used if the durations are not set
+	c := ProverConfig{TrustingPeriod: "336h", MaxClockDrift: "30s"}
+	if d, err := c.GetTrustingPeriod(); err != nil {
+		t.Fatal(err)
+	} else if d != 336*time.Hour {
+		t.Fatalf("unexpected trusting period: %v", d)
+	}
+	if d, err := c.GetMaxClockDrift(); err != nil {
+		t.Fatal(err)
+	} else if d != 30*time.Second {
+		t.Fatalf("unexpected max clock drift: %v", d)
+	}
+	if d, err := (ProverConfig{}).GetMaxClockDrift(); err != nil || d != 0 {
+		t.Fatalf("unexpected max clock drift of the empty config: %v %v", d, err)
+	}
+}
+
+func TestLoadE2EConfigTemplates(t *testing.T) {
+	codec := core.MakeCodec()
+	ethereum.Module{}.RegisterInterfaces(codec.InterfaceRegistry())
+	hd.Module{}.RegisterInterfaces(codec.InterfaceRegistry())
+	Module{}.RegisterInterfaces(codec.InterfaceRegistry())
+
+	files, err := filepath.Glob("../e2e/relayer/configs/templates/*.json.tpl")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) == 0 {
+		t.Fatal("no config templates")
+	}
+	vars := map[string]string{
+		"IBC_ADDRESS":         "0x702E40245797c5a2108A566b3CE2Bf14Bc6aF841",
+		"QBFT_CLIENT_ADDRESS": "0x2F5703804E29F4252FA9405B8D357220d11b3bd9",
+	}
+	for _, file := range files {
+		for _, consensusType := range []string{QBFTConsensusType, IBFT2ConsensusType} {
+			t.Run(filepath.Base(file)+"/"+consensusType, func(t *testing.T) {
+				bz, err := os.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				vars["CONSENSUS_TYPE"] = consensusType
+				var cfg core.ChainProverConfig
+				if err := json.Unmarshal([]byte(os.Expand(string(bz), func(k string) string { return vars[k] })), &cfg); err != nil {
+					t.Fatal(err)
+				}
+				// Init unmarshals and validates the configs as the relayer does on loading config.json
+				if err := cfg.Init(codec); err != nil {
+					t.Fatal(err)
+				}
+				prover, err := cfg.GetProverConfig()
+				if err != nil {
+					t.Fatal(err)
+				}
+				c, ok := prover.(*ProverConfig)
+				if !ok {
+					t.Fatalf("unexpected prover config: %T", prover)
+				}
+				if d, err := c.GetTrustingPeriod(); err != nil {
+					t.Fatal(err)
+				} else if d != 1209600*time.Second {
+					t.Fatalf("unexpected trusting period: %v", d)
+				}
+				if d, err := c.GetMaxClockDrift(); err != nil {
+					t.Fatal(err)
+				} else if d != 30*time.Second {
+					t.Fatalf("unexpected max clock drift: %v", d)
+				}
+				if c.ConsensusTypeAt(0) != consensusType {
+					t.Fatalf("unexpected consensus type: %v", c.ConsensusTypeAt(0))
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInsufficientVoting is returned when not more than 2/3 of the validators sealed the header
+var ErrInsufficientVoting = errors.New("insufficient voting")
+
+// ErrInvalidExtraData is returned when the extra data of a header is not a single RLP list of the QBFT/IBFT2 extra data fields
+var ErrInvalidExtraData = errors.New("invalid extra data")
+
+// ErrInvalidSeal is returned when the signer of a seal cannot be recovered
+var ErrInvalidSeal = errors.New("invalid seal")
+
+// ErrInvalidHeaderRLP is returned when the header of a Header is not the RLP encoding of an ethereum header
+var ErrInvalidHeaderRLP = errors.New("invalid header RLP")
+
+// ErrInvalidProofRLP is returned when a proof is not an RLP list of trie nodes
+var ErrInvalidProofRLP = errors.New("invalid proof RLP")
+
+// InsufficientVotingError is returned when not more than 2/3 of the validators sealed the header.
+// The report describes the seals that were not counted as votes.
+type InsufficientVotingError struct {
+	Count     int
+	Threshold int
+	Report    *SealReport
+}
+
+var _ error = (*InsufficientVotingError)(nil)
+
+func (e *InsufficientVotingError) Error() string {
+	return fmt.Sprintf("%v: %v > %v: %v", ErrInsufficientVoting, e.Count, e.Threshold, e.Report)
+}
+
+func (e *InsufficientVotingError) Unwrap() error {
+	return ErrInsufficientVoting
+}
+
+// CommitmentMismatchError is returned when the commitment stored in the IBC contract does not match the expected one.
+// For non-membership proofs, the expected commitment is the empty hash.
+type CommitmentMismatchError struct {
+	Path     string
+	Height   int64
+	Expected common.Hash
+	Actual   common.Hash
+}
+
+var _ error = (*CommitmentMismatchError)(nil)
+
+func (e *CommitmentMismatchError) Error() string {
+	return fmt.Sprintf("commitment mismatch: path=%v height=%v expected=%v actual=%v", e.Path, e.Height, e.Expected, e.Actual)
+}
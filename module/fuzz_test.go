@@ -0,0 +1,170 @@
+package module
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The fuzz targets decode the inputs from the chain and the relayer, which must be rejected with the typed errors instead of panics.
+// They are seeded with the golden vectors, and run with e.g. `go test ./module -run '^$' -fuzz FuzzParseExtraData`.
+
+// goldenSeeds returns the golden vectors to seed the fuzz targets
+func goldenSeeds(f *testing.F) []*goldenVector {
+	files, err := filepath.Glob(filepath.Join(goldenDir, "*.json"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	var vectors []*goldenVector
+	for _, file := range files {
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		var v goldenVector
+		if err := json.Unmarshal(bz, &v); err != nil {
+			f.Fatal(err)
+		}
+		vectors = append(vectors, &v)
+	}
+	return vectors
+}
+
+func mustEncodeRLP(f *testing.F, val interface{}) []byte {
+	bz, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		f.Fatal(err)
+	}
+	return bz
+}
+
+func FuzzParseExtraData(f *testing.F) {
+	for _, v := range goldenSeeds(f) {
+		var header gethtypes.Header
+		if err := rlp.DecodeBytes(v.Header, &header); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(header.Extra)
+		f.Add(append(common.CopyBytes(header.Extra), 0x80))
+	}
+	vanity := make([]byte, 32)
+	validators := []common.Address{{1}, {2}}
+	f.Add([]byte{})
+	f.Add([]byte{0x80})
+	f.Add(mustEncodeRLP(f, vanity))
+	f.Add(mustEncodeRLP(f, []interface{}{vanity, validators, []interface{}{}, []byte{}, [][]byte{{1}}}))
+	f.Add(mustEncodeRLP(f, []interface{}{vanity, validators, []interface{}{}, []byte{}, [][]byte{}, []byte{}}))
+	f.Add(mustEncodeRLP(f, []interface{}{vanity, [][]byte{{1}}}))
+
+	f.Fuzz(func(t *testing.T, extraBytes []byte) {
+		extra, err := parseExtraData(extraBytes)
+		if err != nil && !errors.Is(err, ErrInvalidExtraData) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		validators, verr := parseValidators(extraBytes)
+		if verr != nil && !errors.Is(verr, ErrInvalidExtraData) {
+			t.Fatalf("unexpected error: %v", verr)
+		}
+		if err == nil {
+			// the extra data with seals must also be parsed as one without seals
+			if verr != nil {
+				t.Fatalf("failed to parse validators of valid extra data: %v", verr)
+			} else if !equalValidators(extra.Validators, validators) {
+				t.Fatalf("unexpected validators: expected=%v actual=%v", extra.Validators, validators)
+			}
+		}
+	})
+}
+
+func FuzzDecodeEthHeader(f *testing.F) {
+	for _, v := range goldenSeeds(f) {
+		f.Add([]byte(v.Header))
+		f.Add([]byte(v.BesuHeaderRlp))
+	}
+	f.Add([]byte{})
+	f.Add([]byte{0xc0})
+
+	f.Fuzz(func(t *testing.T, headerRlp []byte) {
+		h := &Header{BesuHeaderRlp: headerRlp}
+		ethHeader, err := h.EthHeader()
+		if err != nil {
+			if !errors.Is(err, ErrInvalidHeaderRLP) {
+				t.Fatalf("unexpected error: %v", err)
+			} else if !h.GetHeight().IsZero() {
+				t.Fatalf("unexpected height of invalid header: %v", h.GetHeight())
+			}
+			return
+		}
+		if ethHeader.Number == nil {
+			t.Fatal("decoded header has no number")
+		}
+		if _, err := h.getValidators(); err != nil && !errors.Is(err, ErrInvalidExtraData) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func FuzzDecodeAccountProof(f *testing.F) {
+	for _, v := range goldenSeeds(f) {
+		f.Add([]byte(v.AccountStateProof), v.StateRoot.Bytes(), v.IBCAddress.Bytes())
+	}
+	f.Add([]byte{}, []byte{}, []byte{})
+	f.Add([]byte{0xc1, 0x80}, gethtypes.EmptyRootHash.Bytes(), []byte{})
+
+	f.Fuzz(func(t *testing.T, proof, root, address []byte) {
+		h := &Header{AccountStateProof: proof}
+		if _, err := h.decodeAccountProof(); err != nil {
+			if !errors.Is(err, ErrInvalidProofRLP) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+		// an invalid proof must be rejected by the verification without panics
+		_, _ = verifyAccountProof(common.BytesToHash(root), common.BytesToAddress(address), proof)
+	})
+}
+
+func FuzzRecoverSeals(f *testing.F) {
+	for _, v := range goldenSeeds(f) {
+		// the seals of the vectors include the empty ones of the validators that did not seal
+		f.Add([]byte(v.BesuHeaderRlp), []byte(v.Seals[0]), []byte(v.Seals[len(v.Seals)-1]))
+		f.Add([]byte(v.BesuHeaderRlp), []byte(v.Seals[0]), []byte(v.Seals[0]))
+	}
+	f.Add([]byte{}, make([]byte, crypto.SignatureLength), make([]byte, crypto.SignatureLength-1))
+
+	f.Fuzz(func(t *testing.T, headerBytes, seal0, seal1 []byte) {
+		seals := [][]byte{seal0, seal1}
+		recovered, report := recoverSeals(headerBytes, seals)
+		// each seal is either recovered, invalid or a duplicate
+		counted := make([]int, len(seals))
+		for _, r := range recovered {
+			counted[r.Index]++
+			if addr, err := ecrecover(crypto.Keccak256(headerBytes), seals[r.Index]); err != nil || addr != r.Signer {
+				t.Fatalf("unexpected signer of seal %v: expected=%v actual=%v err=%v", r.Index, addr, r.Signer, err)
+			}
+		}
+		for _, s := range report.InvalidSeals {
+			counted[s.Index]++
+		}
+		for _, s := range report.DuplicateSeals {
+			counted[s.Index]++
+		}
+		for i, n := range counted {
+			if n != 1 {
+				t.Fatalf("seal %v is counted %v times: %v", i, n, report)
+			}
+			if len(seals[i]) != crypto.SignatureLength {
+				if _, err := ecrecover(crypto.Keccak256(headerBytes), seals[i]); !errors.Is(err, ErrInvalidSeal) {
+					t.Fatalf("unexpected error of seal %v with length %v: %v", i, len(seals[i]), err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,323 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const goldenDir = "testdata/golden"
+
+var (
+	updateGolden        = flag.Bool("golden.update", false, "regenerate the golden vectors of the simulated chain")
+	recordGolden        = flag.String("golden.record", "", "record a golden vector of the latest block from the Besu node at the URL")
+	recordConsensusType = flag.String("golden.consensus-type", QBFTConsensusType, "consensus type of the Besu node to record from")
+	recordIBCAddress    = flag.String("golden.ibc-address", "", "address of the IBC contract on the Besu node to record from")
+)
+
+// goldenVector is a header built by the prover with its input, which QBFTClient.sol must decode and accept.
+// See testdata/golden/README.md for the format.
+type goldenVector struct {
+	Description   string `json:"description"`
+	ConsensusType string `json:"consensus_type"`
+	// Source is the provenance of a vector recorded from a Besu node, which is empty for the simulated vectors
+	Source *goldenSource `json:"source,omitempty"`
+
+	// Header is the RLP encoding of the header with the seals as the RPC node returns
+	Header     hexutil.Bytes  `json:"header"`
+	IBCAddress common.Address `json:"ibc_address"`
+	// AccountProof is the account proof of the IBC contract as eth_getProof returns
+	AccountProof []hexutil.Bytes `json:"account_proof"`
+
+	BesuHeaderRlp hexutil.Bytes    `json:"besu_header_rlp"`
+	SigningHash   common.Hash      `json:"signing_hash"`
+	Validators    []common.Address `json:"validators"`
+	// Seals are ordered by the validators, and are empty for the validators that did not seal the header
+	Seals             []hexutil.Bytes `json:"seals"`
+	AccountStateProof hexutil.Bytes   `json:"account_state_proof"`
+	StateRoot         common.Hash     `json:"state_root"`
+	StorageRoot       common.Hash     `json:"storage_root"`
+}
+
+// goldenSource is the node and block from which a golden vector is recorded
+type goldenSource struct {
+	// ClientVersion is the result of web3_clientVersion, e.g. "besu/v24.3.0/linux-x86_64/openjdk-java-17"
+	ClientVersion string      `json:"client_version"`
+	ChainID       uint64      `json:"chain_id"`
+	BlockNumber   uint64      `json:"block_number"`
+	BlockHash     common.Hash `json:"block_hash"`
+}
+
+func TestGoldenVectors(t *testing.T) {
+	if *updateGolden {
+		writeSimulatedGoldenVectors(t)
+	}
+	if *recordGolden != "" {
+		recordBesuGoldenVector(t, *recordGolden, *recordConsensusType, common.HexToAddress(*recordIBCAddress))
+	}
+	files, err := filepath.Glob(filepath.Join(goldenDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) == 0 {
+		t.Fatal("no golden vectors")
+	}
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			bz, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var v goldenVector
+			if err := json.Unmarshal(bz, &v); err != nil {
+				t.Fatal(err)
+			}
+			testGoldenVector(t, &v)
+		})
+	}
+}
+
+func testGoldenVector(t *testing.T, v *goldenVector) {
+	var header gethtypes.Header
+	if err := rlp.DecodeBytes(v.Header, &header); err != nil {
+		t.Fatal(err)
+	}
+	if src := v.Source; src != nil {
+		if !strings.HasPrefix(src.ClientVersion, "besu/") {
+			t.Fatalf("vector is not recorded from Besu: %v", src.ClientVersion)
+		} else if src.BlockNumber != header.Number.Uint64() {
+			t.Fatalf("unexpected block number: expected=%v actual=%v", src.BlockNumber, header.Number)
+		}
+	}
+	decoded, err := newGoldenProver(t, v).buildHeaderFromEthHeader(context.Background(), &header, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := decoded.header
+	if !bytes.Equal(h.BesuHeaderRlp, v.BesuHeaderRlp) {
+		t.Fatalf("unexpected besu header rlp:\nexpected=%x\nactual=%x", v.BesuHeaderRlp, h.BesuHeaderRlp)
+	}
+	if hash := crypto.Keccak256Hash(h.BesuHeaderRlp); hash != v.SigningHash {
+		t.Fatalf("unexpected signing hash: expected=%v actual=%v", v.SigningHash, hash)
+	}
+	if len(h.Seals) != len(v.Seals) {
+		t.Fatalf("unexpected number of seals: expected=%v actual=%v", len(v.Seals), len(h.Seals))
+	}
+	for i := range v.Seals {
+		if !bytes.Equal(h.Seals[i], v.Seals[i]) {
+			t.Fatalf("unexpected seal[%v]: expected=%x actual=%x", i, v.Seals[i], h.Seals[i])
+		}
+	}
+	if !bytes.Equal(h.AccountStateProof, v.AccountStateProof) {
+		t.Fatalf("unexpected account state proof:\nexpected=%x\nactual=%x", v.AccountStateProof, h.AccountStateProof)
+	}
+
+	// the light client must derive the same consensus state from the header
+	clientState := &ClientState{IbcStoreAddress: v.IBCAddress.Bytes()}
+	consState, err := clientState.consensusStateFromHeader(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root := common.BytesToHash(consState.Root); root != v.StorageRoot {
+		t.Fatalf("unexpected storage root: expected=%v actual=%v", v.StorageRoot, root)
+	}
+	if validators := bytesToAddresses(consState.Validators); !equalValidators(validators, v.Validators) {
+		t.Fatalf("unexpected validators: expected=%v actual=%v", v.Validators, validators)
+	}
+	if err := verifyCommitSeals(v.Validators, h.Seals, v.SigningHash.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newGoldenProver returns the prover that queries the header and the account proof of the vector
+func newGoldenProver(t *testing.T, v *goldenVector) *Prover {
+	// the chain is never connected as the queries are served by goldenClient
+	pr := NewProver(newTestEthChain(t, "http://127.0.0.1:0", v.IBCAddress), newTestProverConfig(v.ConsensusType))
+	var header gethtypes.Header
+	if err := rlp.DecodeBytes(v.Header, &header); err != nil {
+		t.Fatal(err)
+	}
+	return pr.withClient(&goldenClient{header: &header, proof: &proofResult{AccountProof: v.AccountProof, StorageHash: v.StorageRoot}})
+}
+
+// goldenClient serves the header and the account proof of a golden vector for any block
+type goldenClient struct {
+	header *gethtypes.Header
+	proof  *proofResult
+}
+
+var _ ethClient = (*goldenClient)(nil)
+
+func (cl *goldenClient) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	return cl.header, nil
+}
+
+func (cl *goldenClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (cl *goldenClient) CallContractByNumbers(ctx context.Context, msg ethereum.CallMsg, numbers []*big.Int) ([][]byte, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (cl *goldenClient) GetProof(address common.Address, storageKeys [][]byte, blockNumber *big.Int) (*client.StateProof, error) {
+	return cl.proof.toStateProof()
+}
+
+func (cl *goldenClient) HeaderAndProofByNumber(ctx context.Context, number *big.Int, address common.Address, storageKeys [][]byte) (*gethtypes.Header, *client.StateProof, error) {
+	proof, err := cl.proof.toStateProof()
+	return cl.header, proof, err
+}
+
+func (cl *goldenClient) HeadersByNumbers(ctx context.Context, numbers []*big.Int) ([]*gethtypes.Header, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+// writeSimulatedGoldenVectors writes the vectors of the simulated chain,
+// whose expected values are derived from the chain independently of the prover
+func writeSimulatedGoldenVectors(t *testing.T) {
+	for _, c := range []struct {
+		name          string
+		consensusType string
+		round         uint32
+		// signers are the indices of the validators that seal the header in order
+		signers []int
+	}{
+		{name: "simulated_qbft_all_sealed", consensusType: QBFTConsensusType, signers: []int{0, 1, 2, 3}},
+		{name: "simulated_qbft_round2_gap", consensusType: QBFTConsensusType, round: 2, signers: []int{3, 0, 1}},
+		{name: "simulated_ibft2_all_sealed", consensusType: IBFT2ConsensusType, signers: []int{0, 1, 2, 3}},
+		{name: "simulated_ibft2_round1_gap", consensusType: IBFT2ConsensusType, round: 1, signers: []int{2, 1, 3}},
+	} {
+		chain := newTestChain(t, newTestProverConfig(c.consensusType), 4)
+		chain.SetState(testIBCAddress, common.HexToHash("0x01"), common.HexToHash("0xff"))
+		chain.MineN(2)
+		var signers []common.Address
+		for _, i := range c.signers {
+			signers = append(signers, chain.validators[i])
+		}
+		header := chain.Mine(withRound(c.round), withSigners(signers...))
+		extra, err := parseExtraData(header.Extra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unsealed := *header
+		unsealed.Extra = chain.encodeExtraData(header.Number.Uint64(), extra, false)
+		besuHeaderRlp := mustEncodeHeader(&unsealed)
+
+		seals := make([]hexutil.Bytes, len(chain.validators))
+		for i, signer := range c.signers {
+			seals[signer] = extra.Seals[i]
+		}
+		proof := chain.states[header.Number.Uint64()].Proof(testIBCAddress, nil)
+		var nodes []rlp.RawValue
+		for _, node := range proof.AccountProof {
+			nodes = append(nodes, rlp.RawValue(node))
+		}
+		accountStateProof, err := rlp.EncodeToBytes(nodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeGoldenVector(t, c.name, &goldenVector{
+			Description:       fmt.Sprintf("%v header of the simulated chain at round %v sealed by the validators %v in order", c.consensusType, c.round, c.signers),
+			ConsensusType:     c.consensusType,
+			Header:            mustEncodeHeader(header),
+			IBCAddress:        testIBCAddress,
+			AccountProof:      proof.AccountProof,
+			BesuHeaderRlp:     besuHeaderRlp,
+			SigningHash:       crypto.Keccak256Hash(besuHeaderRlp),
+			Validators:        chain.validators,
+			Seals:             seals,
+			AccountStateProof: accountStateProof,
+			StateRoot:         header.Root,
+			StorageRoot:       proof.StorageHash,
+		})
+	}
+}
+
+// recordBesuGoldenVector records the header built by the prover for the latest block of the Besu node.
+// For QBFT, the signing hash is checked against the block hash that the node returns.
+func recordBesuGoldenVector(t *testing.T, url string, consensusType string, ibcAddress common.Address) {
+	ctx := context.Background()
+	cl, err := client.NewETHClient(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := cl.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var block struct {
+		Hash common.Hash `json:"hash"`
+	}
+	if err := cl.Raw().CallContext(ctx, &block, "eth_getBlockByNumber", toBlockNumArg(header.Number), false); err != nil {
+		t.Fatal(err)
+	}
+	var clientVersion string
+	if err := cl.Raw().CallContext(ctx, &clientVersion, "web3_clientVersion"); err != nil {
+		t.Fatal(err)
+	}
+	chainID, err := cl.ChainID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var proof proofResult
+	if err := cl.Raw().CallContext(ctx, &proof, "eth_getProof", ibcAddress, []common.Hash{}, toBlockNumArg(header.Number)); err != nil {
+		t.Fatal(err)
+	}
+
+	v := &goldenVector{
+		Description:   fmt.Sprintf("%v header recorded from %v at %v", consensusType, clientVersion, header.Number),
+		ConsensusType: consensusType,
+		Source: &goldenSource{
+			ClientVersion: clientVersion,
+			ChainID:       chainID.Uint64(),
+			BlockNumber:   header.Number.Uint64(),
+			BlockHash:     block.Hash,
+		},
+		Header:       mustEncodeHeader(header),
+		IBCAddress:   ibcAddress,
+		AccountProof: proof.AccountProof,
+		StateRoot:    header.Root,
+		StorageRoot:  proof.StorageHash,
+	}
+	decoded, err := newGoldenProver(t, v).buildHeaderFromEthHeader(ctx, header, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := decoded.header
+	v.BesuHeaderRlp = h.BesuHeaderRlp
+	v.SigningHash = crypto.Keccak256Hash(h.BesuHeaderRlp)
+	if consensusType == QBFTConsensusType && v.SigningHash != block.Hash {
+		t.Fatalf("signing hash is not the block hash: block_hash=%v signing_hash=%v", block.Hash, v.SigningHash)
+	}
+	v.Validators = decoded.validators
+	for _, seal := range h.Seals {
+		v.Seals = append(v.Seals, seal)
+	}
+	v.AccountStateProof = h.AccountStateProof
+	writeGoldenVector(t, fmt.Sprintf("besu_%v_%v", consensusType, header.Number), v)
+}
+
+func writeGoldenVector(t *testing.T, name string, v *goldenVector) {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(goldenDir, name+".json"), append(bz, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,10 +1,11 @@
 package module
 
 import (
-	"log"
+	"fmt"
 
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/hyperledger-labs/yui-relayer/core"
@@ -12,20 +13,47 @@ import (
 
 var _ core.Header = (*Header)(nil)
 
+// NewHeader returns a header whose eth header and account proof are validated
+func NewHeader(besuHeaderRlp []byte, seals [][]byte, trustedHeight clienttypes.Height, accountStateProof []byte, validatorContractProof *ValidatorContractProof) (*Header, error) {
+	h := &Header{
+		BesuHeaderRlp:          besuHeaderRlp,
+		Seals:                  seals,
+		TrustedHeight:          trustedHeight,
+		AccountStateProof:      accountStateProof,
+		ValidatorContractProof: validatorContractProof,
+	}
+	if err := h.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
 func (Header) ClientType() string {
 	return QBFT_CLIENT_TYPE
 }
 
+// GetHeight returns the height of the header, or the zero height if the header cannot be decoded,
+// which is rejected by ValidateBasic. Use Height to handle the error.
 func (h *Header) GetHeight() exported.Height {
-	ethHeader, err := h.decodeEthHeader()
+	height, err := h.Height()
+	if err != nil {
+		return clienttypes.ZeroHeight()
+	}
+	return height
+}
+
+// Height returns the height of the header
+func (h *Header) Height() (clienttypes.Height, error) {
+	ethHeader, err := h.EthHeader()
 	if err != nil {
-		log.Panicf("invalid header: %v", h)
+		return clienttypes.Height{}, err
 	}
-	return ethHeightToPB(ethHeader.Number.Uint64())
+	// a header cannot be applied across revisions, so it has the same revision number as its trusted height
+	return ethHeightToPB(h.TrustedHeight.RevisionNumber, ethHeader.Number.Uint64()), nil
 }
 
 func (h *Header) ValidateBasic() error {
-	if _, err := h.decodeEthHeader(); err != nil {
+	if _, err := h.EthHeader(); err != nil {
 		return err
 	}
 	if _, err := h.decodeAccountProof(); err != nil {
@@ -34,30 +62,69 @@ func (h *Header) ValidateBasic() error {
 	return nil
 }
 
-func (h *Header) decodeEthHeader() (*types.Header, error) {
+// EthHeader decodes the eth header from BesuHeaderRlp on each call.
+// The prover keeps the decoded eth header of the headers it builds in decodedHeader instead of decoding them again.
+func (h *Header) EthHeader() (*types.Header, error) {
 	var ethHeader types.Header
 	if err := rlp.DecodeBytes(h.BesuHeaderRlp, &ethHeader); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHeaderRLP, err)
 	}
 	return &ethHeader, nil
 }
 
-func (h *Header) decodeAccountProof() ([][]byte, error) {
-	var decodedProof [][][]byte
-	if err := rlp.DecodeBytes(h.AccountStateProof, &decodedProof); err != nil {
+// getValidators returns the validators that sealed the header,
+// which are in the validator contract proof if it is set, or in the extra data otherwise
+func (h *Header) getValidators() ([]common.Address, error) {
+	ethHeader, err := h.EthHeader()
+	if err != nil {
 		return nil, err
 	}
-	var accountProof [][]byte
-	for i := range decodedProof {
-		b, err := rlp.EncodeToBytes(decodedProof[i])
-		if err != nil {
-			return nil, err
-		}
-		accountProof = append(accountProof, b)
+	return h.validatorsOf(ethHeader)
+}
+
+// validatorsOf returns the validators that sealed the header, where `ethHeader` is the decoded eth header of it
+func (h *Header) validatorsOf(ethHeader *types.Header) ([]common.Address, error) {
+	if h.ValidatorContractProof != nil {
+		return bytesToAddresses(h.ValidatorContractProof.Validators), nil
 	}
-	return accountProof, nil
+	return parseValidators(ethHeader.Extra)
+}
+
+// decodedHeader is a header built by the prover together with its eth header and validators,
+// which are decoded once when the header is built or loaded from the cache
+type decodedHeader struct {
+	header     *Header
+	ethHeader  *types.Header
+	validators []common.Address
+}
+
+func decodeHeader(h *Header) (*decodedHeader, error) {
+	ethHeader, err := h.EthHeader()
+	if err != nil {
+		return nil, err
+	}
+	validators, err := h.validatorsOf(ethHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &decodedHeader{header: h, ethHeader: ethHeader, validators: validators}, nil
+}
+
+// copy returns a copy of the header that shares the decoded fields, so that its trusted height can be set independently
+func (d *decodedHeader) copy() *decodedHeader {
+	h := *d.header
+	return &decodedHeader{header: &h, ethHeader: d.ethHeader, validators: d.validators}
+}
+
+// height returns the height of the header in the revision of its trusted height
+func (d *decodedHeader) height() clienttypes.Height {
+	return ethHeightToPB(d.header.TrustedHeight.RevisionNumber, d.ethHeader.Number.Uint64())
+}
+
+func (h *Header) decodeAccountProof() ([][]byte, error) {
+	return decodeRLPProof(h.AccountStateProof)
 }
 
-func ethHeightToPB(height uint64) clienttypes.Height {
-	return clienttypes.NewHeight(0, uint64(height))
+func ethHeightToPB(revisionNumber, height uint64) clienttypes.Height {
+	return clienttypes.NewHeight(revisionNumber, height)
 }
@@ -0,0 +1,177 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultLivenessWindow is the number of blocks in the window of the liveness tracker of the prover by default
+const DefaultLivenessWindow = 1000
+
+// ValidatorStats is the seal participation of a validator in the blocks of the window
+type ValidatorStats struct {
+	Address common.Address `json:"address"`
+	// Blocks is the number of the recorded blocks in which the address is a validator, which is the sample size of MissRate
+	Blocks int `json:"blocks"`
+	Sealed int `json:"sealed"`
+	Missed int `json:"missed"`
+	// MissRate is Missed / Blocks
+	MissRate float64 `json:"miss_rate"`
+	// Sampled is true if some blocks between the oldest and latest recorded blocks are not recorded,
+	// so that the counts and the rate are of a sample of the blocks rather than of every block
+	Sampled bool `json:"sampled"`
+	// LastSealed is the number of the last block sealed by the validator, or zero if it sealed none
+	LastSealed uint64 `json:"last_sealed"`
+}
+
+// blockParticipation records which validators sealed a block
+type blockParticipation struct {
+	number     uint64
+	validators []common.Address
+	sealed     []bool
+}
+
+// LivenessTracker records which validators sealed each block over a rolling window of the latest blocks.
+// The window is keyed by the block number, so that the blocks that are not recorded do not extend it.
+type LivenessTracker struct {
+	mu     sync.Mutex
+	window uint64
+	// blocks are sorted by number
+	blocks []blockParticipation
+}
+
+// NewLivenessTracker returns a tracker that keeps the blocks recorded among the latest `window` blocks
+func NewLivenessTracker(window uint64) *LivenessTracker {
+	return &LivenessTracker{window: window}
+}
+
+// Record records the seals of the block, which are ordered by `validators` and are nil for the validators that did not seal.
+// Blocks that are already recorded or are older than the window are ignored.
+func (t *LivenessTracker) Record(number uint64, validators []common.Address, orderedSeals [][]byte) {
+	if len(validators) != len(orderedSeals) || t.window == 0 {
+		return
+	}
+	sealed := make([]bool, len(validators))
+	for i, seal := range orderedSeals {
+		sealed[i] = len(seal) > 0
+	}
+	block := blockParticipation{number: number, validators: validators, sealed: sealed}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.blocks) > 0 && number+t.window <= t.blocks[len(t.blocks)-1].number {
+		return
+	}
+	i := sort.Search(len(t.blocks), func(i int) bool { return t.blocks[i].number >= number })
+	if i < len(t.blocks) && t.blocks[i].number == number {
+		return
+	}
+	t.blocks = append(t.blocks, blockParticipation{})
+	copy(t.blocks[i+1:], t.blocks[i:])
+	t.blocks[i] = block
+	// drop the blocks out of the window of the latest block
+	latest := t.blocks[len(t.blocks)-1].number
+	j := sort.Search(len(t.blocks), func(j int) bool { return t.blocks[j].number+t.window > latest })
+	t.blocks = t.blocks[j:]
+}
+
+// Stats returns the statistics of the validators in the window, sorted by the miss rate in descending order
+func (t *LivenessTracker) Stats() []ValidatorStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.blocks) == 0 {
+		return nil
+	}
+	// the recorded blocks are distinct, so they are contiguous if they are as many as the numbers they span
+	sampled := t.blocks[len(t.blocks)-1].number-t.blocks[0].number+1 != uint64(len(t.blocks))
+	stats := make(map[common.Address]*ValidatorStats)
+	for _, block := range t.blocks {
+		for i, val := range block.validators {
+			s, ok := stats[val]
+			if !ok {
+				s = &ValidatorStats{Address: val, Sampled: sampled}
+				stats[val] = s
+			}
+			s.Blocks++
+			if block.sealed[i] {
+				s.Sealed++
+				s.LastSealed = block.number
+			}
+		}
+	}
+	var res []ValidatorStats
+	for _, s := range stats {
+		s.Missed = s.Blocks - s.Sealed
+		s.MissRate = float64(s.Missed) / float64(s.Blocks)
+		res = append(res, *s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].MissRate != res[j].MissRate {
+			return res[i].MissRate > res[j].MissRate
+		}
+		return res[i].Address.Cmp(res[j].Address) < 0
+	})
+	return res
+}
+
+// Range returns the numbers of the oldest and latest blocks recorded, and the number of the blocks recorded
+func (t *LivenessTracker) Range() (from, to uint64, count int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.blocks) == 0 {
+		return 0, 0, 0
+	}
+	return t.blocks[0].number, t.blocks[len(t.blocks)-1].number, len(t.blocks)
+}
+
+// Liveness returns the tracker of the blocks whose headers have been built by the prover.
+// As the prover builds the headers of only some blocks, its stats are sampled; use ScanLiveness for every block.
+func (pr *Prover) Liveness() *LivenessTracker {
+	return pr.liveness
+}
+
+// ScanLiveness records the seals of the blocks in [`from`, `to`] to the tracker
+func (pr *Prover) ScanLiveness(ctx context.Context, tracker *LivenessTracker, from, to uint64) error {
+	if from == 0 {
+		// the genesis block has no seals
+		from = 1
+	}
+	for start := from; start <= to; start += headerBatchSize {
+		var numbers []*big.Int
+		for bn := start; bn <= to && bn < start+headerBatchSize; bn++ {
+			numbers = append(numbers, new(big.Int).SetUint64(bn))
+		}
+		headers, err := pr.client.HeadersByNumbers(ctx, numbers)
+		if err != nil {
+			return err
+		}
+		var extras []*ExtraData
+		for _, header := range headers {
+			extra, err := pr.getExtraData(header)
+			if err != nil {
+				return fmt.Errorf("failed to parse the extra data: number=%v: %v", header.Number, err)
+			}
+			extras = append(extras, extra)
+		}
+		validatorSets, err := pr.selectValidators(ctx, headers, extras)
+		if err != nil {
+			return err
+		}
+		for i, header := range headers {
+			extra, validators := extras[i], validatorSets[i]
+			headerBytes, err := pr.encodeHeaderWithoutSeals(*header, *extra)
+			if err != nil {
+				return err
+			}
+			recovered, report := recoverSeals(headerBytes, extra.Seals)
+			orderedSeals, _ := orderSeals(validators, recovered, report)
+			tracker.Record(header.Number.Uint64(), validators, orderedSeals)
+		}
+	}
+	return nil
+}
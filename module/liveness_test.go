@@ -0,0 +1,64 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLivenessTrackerWindow(t *testing.T) {
+	validators := []common.Address{{1}, {2}}
+	seals := [][]byte{{1}, nil}
+	tracker := NewLivenessTracker(10)
+	// the blocks are recorded sparsely, e.g. only the headers built by the prover
+	for _, number := range []uint64{1, 5, 9, 12, 20} {
+		tracker.Record(number, validators, seals)
+	}
+	if from, to, count := tracker.Range(); from != 12 || to != 20 || count != 2 {
+		t.Fatalf("unexpected range: from=%v to=%v count=%v", from, to, count)
+	}
+	// a block out of the window is ignored
+	tracker.Record(10, validators, seals)
+	// a block in the window is inserted in order
+	tracker.Record(15, validators, seals)
+	if from, to, count := tracker.Range(); from != 12 || to != 20 || count != 3 {
+		t.Fatalf("unexpected range: from=%v to=%v count=%v", from, to, count)
+	}
+	stats := tracker.Stats()
+	if len(stats) != 2 || stats[0].Address != validators[1] || stats[0].Missed != 3 || stats[1].Sealed != 3 || stats[1].LastSealed != 20 {
+		t.Fatalf("unexpected stats: %v", stats)
+	}
+	// the stats of the sparse blocks are labeled as sampled, with the sample size in Blocks
+	for _, s := range stats {
+		if !s.Sampled || s.Blocks != 3 {
+			t.Fatalf("unexpected stats: %v", s)
+		}
+	}
+}
+
+func TestLivenessTrackerContiguous(t *testing.T) {
+	validators := []common.Address{{1}, {2}}
+	tracker := NewLivenessTracker(10)
+	if stats := tracker.Stats(); len(stats) != 0 {
+		t.Fatalf("unexpected stats: %v", stats)
+	}
+	// every block is recorded, e.g. by ScanLiveness
+	for number := uint64(1); number <= 15; number++ {
+		seals := [][]byte{{1}, nil}
+		if number%2 == 0 {
+			seals[1] = []byte{1}
+		}
+		tracker.Record(number, validators, seals)
+	}
+	stats := tracker.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("unexpected stats: %v", stats)
+	}
+	// blocks 6 to 15 are in the window, in which the second validator sealed the even ones
+	if s := stats[0]; s.Address != validators[1] || s.Sampled || s.Blocks != 10 || s.Missed != 5 || s.MissRate != 0.5 || s.LastSealed != 14 {
+		t.Fatalf("unexpected stats: %v", s)
+	}
+	if s := stats[1]; s.Address != validators[0] || s.Sampled || s.Blocks != 10 || s.Missed != 0 || s.LastSealed != 15 {
+		t.Fatalf("unexpected stats: %v", s)
+	}
+}
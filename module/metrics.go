@@ -0,0 +1,178 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	meterName     = "github.com/datachainlab/besu-ibc-relay-prover"
+	namespaceRoot = "qbft"
+)
+
+// the instruments are created once and never reassigned, so they can be read without synchronization
+var (
+	headerBuildDuration          api.Float64Histogram
+	getProofDuration             api.Float64Histogram
+	getProofErrorsCounter        api.Int64Counter
+	sealCountGauge               *metrics.Int64SyncGauge
+	sealThresholdGauge           *metrics.Int64SyncGauge
+	validatorSetSizeGauge        *metrics.Int64SyncGauge
+	clientHeightGapGauge         *metrics.Int64SyncGauge
+	trustingPeriodRemainingGauge *metrics.Int64SyncGauge
+)
+
+func init() {
+	// the global MeterProvider delegates the instruments to the provider set by otel.SetMeterProvider,
+	// so the metrics are exported through the provider of the relayer once it is set
+	if err := createInstruments(otel.GetMeterProvider().Meter(meterName)); err != nil {
+		panic(err)
+	}
+}
+
+func createInstruments(meter api.Meter) error {
+	var err error
+
+	name := fmt.Sprintf("%s.header_build_duration", namespaceRoot)
+	if headerBuildDuration, err = meter.Float64Histogram(
+		name,
+		api.WithUnit("ms"),
+		api.WithDescription("time taken to build a header including its seals and proofs"),
+	); err != nil {
+		return fmt.Errorf("failed to create the instrument %s: %v", name, err)
+	}
+
+	name = fmt.Sprintf("%s.get_proof_duration", namespaceRoot)
+	if getProofDuration, err = meter.Float64Histogram(
+		name,
+		api.WithUnit("ms"),
+		api.WithDescription("latency of eth_getProof"),
+	); err != nil {
+		return fmt.Errorf("failed to create the instrument %s: %v", name, err)
+	}
+
+	name = fmt.Sprintf("%s.get_proof_errors", namespaceRoot)
+	if getProofErrorsCounter, err = meter.Int64Counter(
+		name,
+		api.WithUnit("1"),
+		api.WithDescription("number of failed eth_getProof"),
+	); err != nil {
+		return fmt.Errorf("failed to create the instrument %s: %v", name, err)
+	}
+
+	name = fmt.Sprintf("%s.seal_count", namespaceRoot)
+	if sealCountGauge, err = metrics.NewInt64SyncGauge(
+		meter,
+		name,
+		api.WithUnit("1"),
+		api.WithDescription("number of the validators that sealed the latest header built"),
+	); err != nil {
+		return fmt.Errorf("failed to create the instrument %s: %v", name, err)
+	}
+
+	name = fmt.Sprintf("%s.seal_threshold", namespaceRoot)
+	if sealThresholdGauge, err = metrics.NewInt64SyncGauge(
+		meter,
+		name,
+		api.WithUnit("1"),
+		api.WithDescription("number of seals that the latest header built must exceed"),
+	); err != nil {
+		return fmt.Errorf("failed to create the instrument %s: %v", name, err)
+	}
+
+	name = fmt.Sprintf("%s.validator_set_size", namespaceRoot)
+	if validatorSetSizeGauge, err = metrics.NewInt64SyncGauge(
+		meter,
+		name,
+		api.WithUnit("1"),
+		api.WithDescription("number of the validators of the latest header built"),
+	); err != nil {
+		return fmt.Errorf("failed to create the instrument %s: %v", name, err)
+	}
+
+	name = fmt.Sprintf("%s.client_height_gap", namespaceRoot)
+	if clientHeightGapGauge, err = metrics.NewInt64SyncGauge(
+		meter,
+		name,
+		api.WithUnit("1"),
+		api.WithDescription("number of blocks between the latest finalized header and the latest height of the client on the counterparty chain"),
+	); err != nil {
+		return fmt.Errorf("failed to create the instrument %s: %v", name, err)
+	}
+
+	name = fmt.Sprintf("%s.trusting_period_remaining", namespaceRoot)
+	if trustingPeriodRemainingGauge, err = metrics.NewInt64SyncGauge(
+		meter,
+		name,
+		api.WithUnit("s"),
+		api.WithDescription("time remaining until the client on the counterparty chain expires"),
+	); err != nil {
+		return fmt.Errorf("failed to create the instrument %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// durationMilliseconds returns `d` in fractional milliseconds, keeping the sub-millisecond precision
+func durationMilliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1e3
+}
+
+func chainAttrs(chainID string) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.Key("chain_id").String(chainID)}
+}
+
+// clientAttrs returns the attributes of the client of the chain on `counterparty`
+func (pr *Prover) clientAttrs(counterparty core.ChainInfo) []attribute.KeyValue {
+	attrs := append(chainAttrs(pr.chain.ChainID()), attribute.Key("counterparty_chain_id").String(counterparty.ChainID()))
+	if pr.counterpartyPath != nil {
+		attrs = append(attrs, attribute.Key("client_id").String(pr.counterpartyPath.ClientID))
+	}
+	return attrs
+}
+
+// meteredClient records the latency and errors of the proof queries of the client
+type meteredClient struct {
+	ethClient
+	attrs []attribute.KeyValue
+}
+
+var _ ethClient = meteredClient{}
+
+func newMeteredClient(cl ethClient, chainID string) meteredClient {
+	return meteredClient{ethClient: cl, attrs: chainAttrs(chainID)}
+}
+
+func (cl meteredClient) GetProof(address common.Address, storageKeys [][]byte, blockNumber *big.Int) (*client.StateProof, error) {
+	start := time.Now()
+	proof, err := cl.ethClient.GetProof(address, storageKeys, blockNumber)
+	cl.recordGetProof(start, false, err)
+	return proof, err
+}
+
+func (cl meteredClient) HeaderAndProofByNumber(ctx context.Context, number *big.Int, address common.Address, storageKeys [][]byte) (*gethtypes.Header, *client.StateProof, error) {
+	start := time.Now()
+	header, proof, err := cl.ethClient.HeaderAndProofByNumber(ctx, number, address, storageKeys)
+	cl.recordGetProof(start, true, err)
+	return header, proof, err
+}
+
+// recordGetProof records a proof query, where `batched` indicates that the proof was queried together with the header
+func (cl meteredClient) recordGetProof(start time.Time, batched bool, err error) {
+	attrs := api.WithAttributes(append(cl.attrs, attribute.Key("batched").Bool(batched))...)
+	getProofDuration.Record(context.Background(), durationMilliseconds(time.Since(start)), attrs)
+	if err != nil {
+		getProofErrorsCounter.Add(context.Background(), 1, attrs)
+	}
+}
@@ -0,0 +1,117 @@
+package module
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// FrozenHeight is set to the client state when the client is frozen due to a misbehaviour
+var FrozenHeight = clienttypes.NewHeight(0, 1)
+
+var _ exported.ClientMessage = (*Misbehaviour)(nil)
+
+func (Misbehaviour) ClientType() string {
+	return QBFT_CLIENT_TYPE
+}
+
+// ValidateBasic checks that the misbehaviour consists of two different headers at the same height
+func (m *Misbehaviour) ValidateBasic() error {
+	if m.Header1 == nil || m.Header2 == nil {
+		return fmt.Errorf("misbehaviour must have two headers")
+	}
+	if err := m.Header1.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid header 1: %v", err)
+	}
+	if err := m.Header2.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid header 2: %v", err)
+	}
+	if h1, h2 := m.Header1.GetHeight(), m.Header2.GetHeight(); !h1.EQ(h2) {
+		return fmt.Errorf("headers must be at the same height: %v != %v", h1, h2)
+	}
+	if bytes.Equal(crypto.Keccak256(m.Header1.BesuHeaderRlp), crypto.Keccak256(m.Header2.BesuHeaderRlp)) {
+		return fmt.Errorf("headers must have different hashes")
+	}
+	return nil
+}
+
+// verifyMisbehaviour verifies that both headers of the misbehaviour are sealed by more than 2/3 of their trusted validators
+func (cs *ClientState) verifyMisbehaviour(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, misbehaviour *Misbehaviour) error {
+	if err := misbehaviour.ValidateBasic(); err != nil {
+		return errorsmod.Wrap(clienttypes.ErrInvalidMisbehaviour, err.Error())
+	}
+	for i, header := range []*Header{misbehaviour.Header1, misbehaviour.Header2} {
+		trustedConsState, found := GetConsensusState(clientStore, cdc, header.TrustedHeight)
+		if !found {
+			return errorsmod.Wrapf(clienttypes.ErrConsensusStateNotFound, "could not get trusted consensus state from clientStore for header %v at TrustedHeight: %s", i+1, header.TrustedHeight)
+		}
+		if err := cs.verifyMisbehaviourHeader(trustedConsState, header, ctx.BlockTime()); err != nil {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidMisbehaviour, "failed to verify header %v: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// verifyMisbehaviourHeader verifies that the header is valid and sealed by more than 2/3 of the trusted validators.
+// Unlike verifyHeader, the timestamp of the header is not checked because conflicting headers may have any timestamps.
+func (cs *ClientState) verifyMisbehaviourHeader(trustedConsState *ConsensusState, header *Header, now time.Time) error {
+	ethHeader, err := header.EthHeader()
+	if err != nil {
+		return err
+	}
+	if height := ethHeader.Number.Uint64(); height <= header.TrustedHeight.RevisionHeight {
+		return fmt.Errorf("header height must be greater than trusted height: %v <= %v", height, header.TrustedHeight.RevisionHeight)
+	}
+	if cs.isExpired(trustedConsState.Timestamp, now) {
+		return fmt.Errorf("trusted consensus state is expired: timestamp=%v trusting_period=%v now=%v", trustedConsState.Timestamp, cs.TrustingPeriod, now.Unix())
+	}
+	consState, err := cs.consensusStateFromEthHeader(header, ethHeader)
+	if err != nil {
+		return err
+	}
+	headerHash := crypto.Keccak256(header.BesuHeaderRlp)
+	if err := verifyCommitSeals(bytesToAddresses(consState.Validators), header.Seals, headerHash); err != nil {
+		return err
+	}
+	trustedValidators := bytesToAddresses(trustedConsState.Validators)
+	if count := countTrustedSeals(trustedValidators, header.Seals, headerHash); count*3 <= len(trustedValidators)*2 {
+		return fmt.Errorf("insufficient seals by the trusted validators: %v of %v", count, len(trustedValidators))
+	}
+	return nil
+}
+
+// CheckForMisbehaviour returns true for a verified misbehaviour,
+// or for a header that conflicts with the consensus state already stored at its height
+func (cs *ClientState) CheckForMisbehaviour(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) bool {
+	switch msg := clientMsg.(type) {
+	case *Misbehaviour:
+		// the misbehaviour has already been verified in VerifyClientMessage
+		return true
+	case *Header:
+		existingConsState, found := GetConsensusState(clientStore, cdc, msg.GetHeight())
+		if !found {
+			return false
+		}
+		consState, err := cs.consensusStateFromHeader(msg)
+		if err != nil {
+			return false
+		}
+		return !bytes.Equal(cdc.MustMarshal(existingConsState), cdc.MustMarshal(consState))
+	default:
+		return false
+	}
+}
+
+// UpdateStateOnMisbehaviour freezes the client
+func (cs *ClientState) UpdateStateOnMisbehaviour(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) {
+	cs.FrozenHeight = FrozenHeight
+	setClientState(clientStore, cdc, cs)
+}
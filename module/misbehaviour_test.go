@@ -0,0 +1,128 @@
+package module
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newTestForks returns two chains that share the blocks up to `forkHeight` and produce different blocks after it.
+// The block after the fork height of the second chain is produced by `fork`, or committed in another round if nil.
+func newTestForks(t *testing.T, forkHeight int, fork func(chain *testChain)) (*testChain, *testChain) {
+	config := newTestProverConfig(QBFTConsensusType)
+	// the keys of the chains are the same as they are deterministic
+	chain1, chain2 := newTestChain(t, config, 4), newTestChain(t, config, 4)
+	chain1.MineN(forkHeight)
+	chain2.MineN(forkHeight)
+	chain1.Mine()
+	if fork != nil {
+		fork(chain2)
+	} else {
+		// the block committed in another round has a different hash
+		chain2.Mine(withRound(1))
+	}
+	if chain1.besuHash(chain1.Header(uint64(forkHeight))) != chain2.besuHash(chain2.Header(uint64(forkHeight))) {
+		t.Fatal("the chains are not forked at the height")
+	}
+	return chain1, chain2
+}
+
+func TestMisbehaviour(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		// misbehaviour returns the misbehaviour from the headers of the forks after the trusted height
+		misbehaviour func(t *testing.T, pr1, pr2 *Prover, lc *testLightClient) *Misbehaviour
+		// fork produces the block of the second chain at the height of the misbehaviour
+		fork func(chain *testChain)
+		// err is the substring of the error, or empty if the client is frozen
+		err string
+	}{
+		{name: "same height with different hashes", misbehaviour: func(t *testing.T, pr1, pr2 *Prover, lc *testLightClient) *Misbehaviour {
+			trusted := lc.clientState.LatestHeight
+			return &Misbehaviour{Header1: newTestHeader(t, pr1, 3, trusted), Header2: newTestHeader(t, pr2, 3, trusted)}
+		}},
+		{name: "identical headers", misbehaviour: func(t *testing.T, pr1, pr2 *Prover, lc *testLightClient) *Misbehaviour {
+			trusted := lc.clientState.LatestHeight
+			return &Misbehaviour{Header1: newTestHeader(t, pr1, 3, trusted), Header2: newTestHeader(t, pr1, 3, trusted)}
+		}, err: "headers must have different hashes"},
+		{name: "different heights", misbehaviour: func(t *testing.T, pr1, pr2 *Prover, lc *testLightClient) *Misbehaviour {
+			trusted := lc.clientState.LatestHeight
+			return &Misbehaviour{Header1: newTestHeader(t, pr1, 3, trusted), Header2: newTestHeader(t, pr2, 4, trusted)}
+		}, err: "headers must be at the same height"},
+		{name: "insufficient trusted seals", misbehaviour: func(t *testing.T, pr1, pr2 *Prover, lc *testLightClient) *Misbehaviour {
+			trusted := lc.clientState.LatestHeight
+			return &Misbehaviour{Header1: newTestHeader(t, pr1, 3, trusted), Header2: newTestHeader(t, pr2, 3, trusted)}
+		}, fork: func(chain *testChain) {
+			// half of the trusted validators is enough to update the client, but not to freeze it
+			next := append(chain.NewKeys(2), chain.validators[:2]...)
+			sortAddresses(next)
+			chain.Mine(withValidators(next...))
+		}, err: "insufficient seals by the trusted validators"},
+		{name: "insufficient own seals", misbehaviour: func(t *testing.T, pr1, pr2 *Prover, lc *testLightClient) *Misbehaviour {
+			trusted := lc.clientState.LatestHeight
+			header2 := newTestHeader(t, pr2, 3, trusted)
+			header2.Seals[2], header2.Seals[3] = nil, nil
+			return &Misbehaviour{Header1: newTestHeader(t, pr1, 3, trusted), Header2: header2}
+		}, err: "insufficient voting"},
+		{name: "unknown trusted height", misbehaviour: func(t *testing.T, pr1, pr2 *Prover, lc *testLightClient) *Misbehaviour {
+			trusted := pr1.newHeight(1)
+			return &Misbehaviour{Header1: newTestHeader(t, pr1, 3, trusted), Header2: newTestHeader(t, pr2, 3, trusted)}
+		}, err: "could not get trusted consensus state"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			chain1, chain2 := newTestForks(t, 2, c.fork)
+			chain1.Mine()
+			chain2.Mine()
+			pr1, pr2 := newTestProver(t, chain1), newTestProver(t, chain2)
+			lc := newTestLightClient(t, pr1, 2)
+
+			err := lc.update(c.misbehaviour(t, pr1, pr2, lc), time.Unix(int64(chain1.Head().Time), 0))
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("unexpected error: expected=%q actual=%v", c.err, err)
+				}
+				if !lc.clientState.FrozenHeight.IsZero() {
+					t.Fatal("the client is frozen by an invalid misbehaviour")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !lc.clientState.FrozenHeight.EQ(FrozenHeight) {
+				t.Fatalf("unexpected frozen height: expected=%v actual=%v", FrozenHeight, lc.clientState.FrozenHeight)
+			}
+		})
+	}
+}
+
+func TestCheckForMisbehaviourConflictingHeader(t *testing.T) {
+	// CheckForMisbehaviour compares the consensus states, so the state of the fork must be different
+	chain1, chain2 := newTestForks(t, 2, func(chain *testChain) {
+		chain.SetState(testIBCAddress, common.HexToHash("0x01"), common.HexToHash("0x01"))
+		chain.Mine()
+	})
+	pr1, pr2 := newTestProver(t, chain1), newTestProver(t, chain2)
+	lc := newTestLightClient(t, pr1, 2)
+	trusted := lc.clientState.LatestHeight
+	now := time.Unix(int64(chain1.Head().Time), 0)
+
+	header1 := newTestHeader(t, pr1, 3, trusted)
+	if err := lc.update(header1, now); err != nil {
+		t.Fatal(err)
+	}
+	// the same header again is not a misbehaviour
+	if err := lc.update(header1, now); err != nil {
+		t.Fatal(err)
+	} else if !lc.clientState.FrozenHeight.IsZero() {
+		t.Fatal("the client is frozen by the same header")
+	}
+	// a valid header that conflicts with the stored consensus state freezes the client
+	if err := lc.update(newTestHeader(t, pr2, 3, trusted), now); err != nil {
+		t.Fatal(err)
+	}
+	if !lc.clientState.FrozenHeight.EQ(FrozenHeight) {
+		t.Fatalf("unexpected frozen height: expected=%v actual=%v", FrozenHeight, lc.clientState.FrozenHeight)
+	}
+}
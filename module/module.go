@@ -8,13 +8,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// ModuleName is the name of the module
+const ModuleName = "ibft2-prover"
+
 type Module struct{}
 
 var _ config.ModuleI = (*Module)(nil)
 
 // Name returns the name of the module
 func (Module) Name() string {
-	return "ibft2-prover"
+	return ModuleName
 }
 
 // RegisterInterfaces register the module interfaces to protobuf Any.
@@ -31,9 +34,14 @@ func (Module) RegisterInterfaces(registry codectypes.InterfaceRegistry) {
 		(*exported.ConsensusState)(nil),
 		&ConsensusState{},
 	)
+	registry.RegisterImplementations(
+		(*exported.ClientMessage)(nil),
+		&Header{},
+		&Misbehaviour{},
+	)
 }
 
 // GetCmd returns the command
 func (Module) GetCmd(ctx *config.Context) *cobra.Command {
-	return nil
+	return qbftCmd(ctx)
 }
@@ -0,0 +1,91 @@
+package module
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// commitmentStorageKey returns the storage key of the commitment for `path` in the IBC contract
+func commitmentStorageKey(path []byte, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(append(
+		crypto.Keccak256Hash(path).Bytes(),
+		slot.Bytes()...,
+	))
+}
+
+// decodeRLPProof decodes a proof encoded as an RLP list of trie nodes into the RLP encodings of each node
+func decodeRLPProof(proof []byte) ([][]byte, error) {
+	var decodedProof [][][]byte
+	if err := rlp.DecodeBytes(proof, &decodedProof); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProofRLP, err)
+	}
+	var nodes [][]byte
+	for i := range decodedProof {
+		b, err := rlp.EncodeToBytes(decodedProof[i])
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, b)
+	}
+	return nodes, nil
+}
+
+// verifyMerkleProof verifies the merkle patricia trie proof of `key` against `root`.
+// It returns the value of `key`, or nil if the proof proves the absence of `key`.
+func verifyMerkleProof(root common.Hash, key []byte, nodes [][]byte) ([]byte, error) {
+	if root == types.EmptyRootHash {
+		return nil, nil
+	}
+	db := memorydb.New()
+	for _, node := range nodes {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	return trie.VerifyProof(root, crypto.Keccak256(key), db)
+}
+
+// verifyAccountProof verifies the account proof of `address` against `stateRoot` and returns the storage root of the account
+func verifyAccountProof(stateRoot common.Hash, address common.Address, accountProof []byte) (common.Hash, error) {
+	nodes, err := decodeRLPProof(accountProof)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to decode account proof: %v", err)
+	}
+	value, err := verifyMerkleProof(stateRoot, address.Bytes(), nodes)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to verify account proof: %v", err)
+	} else if value == nil {
+		return common.Hash{}, fmt.Errorf("account not found: address=%v", address)
+	}
+	var account types.StateAccount
+	if err := rlp.DecodeBytes(value, &account); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to decode account: %v", err)
+	}
+	return account.Root, nil
+}
+
+// verifyStorageProof verifies the storage proof of `key` against `storageRoot` and returns the value stored at `key`.
+// It returns an empty hash if the proof proves the absence of `key`.
+func verifyStorageProof(storageRoot common.Hash, key common.Hash, storageProof []byte) (common.Hash, error) {
+	nodes, err := decodeRLPProof(storageProof)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to decode storage proof: %v", err)
+	}
+	value, err := verifyMerkleProof(storageRoot, key.Bytes(), nodes)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to verify storage proof: %v", err)
+	} else if value == nil {
+		return common.Hash{}, nil
+	}
+	var content []byte
+	if err := rlp.DecodeBytes(value, &content); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to decode storage value: %v", err)
+	}
+	return common.BytesToHash(content), nil
+}
@@ -0,0 +1,174 @@
+package module
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+// proofList collects the nodes written by trie.Prove in order
+type proofList []hexutil.Bytes
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+func (l *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+func newGethTrie() *trie.Trie {
+	return trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+}
+
+// proveGethTrie returns the proof of the hashed `key` in the RLP list format of the headers
+func proveGethTrie(t *testing.T, tr *trie.Trie, key []byte) []byte {
+	var nodes proofList
+	if err := tr.Prove(crypto.Keccak256(key), &nodes); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := encodeProofNodes(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+func TestVerifyStorageProof(t *testing.T) {
+	storage := newGethTrie()
+	for i := int64(1); i <= 100; i++ {
+		value, err := rlp.EncodeToBytes(common.TrimLeftZeroes(common.BigToHash(big.NewInt(i * 7)).Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		storage.MustUpdate(crypto.Keccak256(common.BigToHash(big.NewInt(i)).Bytes()), value)
+	}
+	root := storage.Hash()
+
+	// membership
+	key := common.BigToHash(big.NewInt(42))
+	proof := proveGethTrie(t, storage, key.Bytes())
+	if value, err := verifyStorageProof(root, key, proof); err != nil {
+		t.Fatal(err)
+	} else if expected := common.BigToHash(big.NewInt(42 * 7)); value != expected {
+		t.Fatalf("unexpected value: expected=%v actual=%v", expected, value)
+	}
+
+	// non-membership
+	absent := common.BigToHash(big.NewInt(1000))
+	if value, err := verifyStorageProof(root, absent, proveGethTrie(t, storage, absent.Bytes())); err != nil {
+		t.Fatal(err)
+	} else if value != (common.Hash{}) {
+		t.Fatalf("unexpected value of absent key: %v", value)
+	}
+	emptyProof, err := encodeProofNodes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := verifyStorageProof(gethtypes.EmptyRootHash, absent, emptyProof); err != nil {
+		t.Fatal(err)
+	} else if value != (common.Hash{}) {
+		t.Fatalf("unexpected value in empty trie: %v", value)
+	}
+
+	// the proof of a key does not prove another key
+	if value, err := verifyStorageProof(root, common.BigToHash(big.NewInt(43)), proof); err == nil && value != (common.Hash{}) {
+		t.Fatalf("proof of %v proves another key: %v", key, value)
+	}
+
+	// tampered proofs
+	nodes, err := decodeRLPProof(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range nodes {
+		tampered := make([]hexutil.Bytes, len(nodes))
+		for j := range nodes {
+			tampered[j] = common.CopyBytes(nodes[j])
+		}
+		// flip a byte of the first hash in the node, or of the value in the leaf node, keeping the RLP structure
+		if j := bytes.IndexByte(tampered[i][1:], 0xa0); j >= 0 {
+			tampered[i][j+2] ^= 1
+		} else {
+			tampered[i][len(tampered[i])-1] ^= 1
+		}
+		bz, err := encodeProofNodes(tampered)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value, err := verifyStorageProof(root, key, bz); err == nil {
+			t.Fatalf("tampered node %v is accepted: value=%v", i, value)
+		}
+	}
+	if _, err := verifyStorageProof(crypto.Keccak256Hash([]byte("other root")), key, proof); err == nil {
+		t.Fatal("proof is accepted against another root")
+	}
+	truncated, err := encodeProofNodes(func() []hexutil.Bytes {
+		var l []hexutil.Bytes
+		for _, n := range nodes[:len(nodes)-1] {
+			l = append(l, n)
+		}
+		return l
+	}())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyStorageProof(root, key, truncated); err == nil {
+		t.Fatal("truncated proof is accepted")
+	}
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	state := newGethTrie()
+	storageRoot := crypto.Keccak256Hash([]byte("storage root"))
+	for i := 0; i < 50; i++ {
+		account := &gethtypes.StateAccount{
+			Nonce:    uint64(i),
+			Balance:  uint256.NewInt(uint64(i)),
+			Root:     gethtypes.EmptyRootHash,
+			CodeHash: gethtypes.EmptyCodeHash.Bytes(),
+		}
+		addr := common.BigToAddress(big.NewInt(int64(i)))
+		if addr == testIBCAddress {
+			continue
+		}
+		bz, err := rlp.EncodeToBytes(account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.MustUpdate(crypto.Keccak256(addr.Bytes()), bz)
+	}
+	bz, err := rlp.EncodeToBytes(&gethtypes.StateAccount{
+		Nonce:    1,
+		Balance:  uint256.NewInt(0),
+		Root:     storageRoot,
+		CodeHash: gethtypes.EmptyCodeHash.Bytes(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.MustUpdate(crypto.Keccak256(testIBCAddress.Bytes()), bz)
+	root := state.Hash()
+
+	if actual, err := verifyAccountProof(root, testIBCAddress, proveGethTrie(t, state, testIBCAddress.Bytes())); err != nil {
+		t.Fatal(err)
+	} else if actual != storageRoot {
+		t.Fatalf("unexpected storage root: expected=%v actual=%v", storageRoot, actual)
+	}
+
+	absent := common.HexToAddress("0x000000000000000000000000000000000000dead")
+	if _, err := verifyAccountProof(root, absent, proveGethTrie(t, state, absent.Bytes())); err == nil {
+		t.Fatal("account proof of an absent account is accepted")
+	}
+}
@@ -3,42 +3,108 @@ package module
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"path/filepath"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
 	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/relay/ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/hyperledger-labs/yui-relayer/core"
+	api "go.opentelemetry.io/otel/metric"
 )
 
+// IBCCommitmentsSlot is the default storage slot of the commitments mapping in the IBC contract of yui-ibc-solidity
 // keccak256(abi.encode(uint256(keccak256("ibc.commitment")) - 1)) & ~bytes32(uint256(0xff))
 var IBCCommitmentsSlot = common.HexToHash("1ee222554989dda120e26ecacf756fe1235cd8d726706b57517715dde4f0c900")
 
+// maxFinalizedHeaderLookback is the maximum number of blocks to look back for the header that passes the seal check
+const maxFinalizedHeaderLookback = 64
+
+// headerBatchSize is the number of headers queried in a JSON-RPC batch
+const headerBatchSize = 32
+
+// DefaultMaxTransitionSearchRange is the maximum number of blocks searched for the validator set changes by default
+const DefaultMaxTransitionSearchRange = 10000
+
 type Prover struct {
 	chain  *ethereum.Chain
+	client ethClient
 	config ProverConfig
+	cache  *headerCache
+
+	liveness *LivenessTracker
+
+	// counterpartyPath is the path end of the counterparty chain, which has the client of this chain
+	counterpartyPath *core.PathEnd
 }
 
 var _ core.Prover = (*Prover)(nil)
 
 func NewProver(chain *ethereum.Chain, config ProverConfig) *Prover {
-	return &Prover{chain: chain, config: config}
+	return &Prover{
+		chain:    chain,
+		client:   newMeteredClient(rpcClient{chain.Client()}, chain.ChainID()),
+		config:   config,
+		cache:    newHeaderCache(config.GetHeaderCacheSize()),
+		liveness: NewLivenessTracker(config.GetLivenessWindow()),
+	}
+}
+
+// withClient returns a copy of the prover that uses `client` to query the chain
+func (pr *Prover) withClient(client ethClient) *Prover {
+	return &Prover{chain: pr.chain, client: client, config: pr.config, cache: pr.cache, liveness: pr.liveness}
 }
 
 // Init implements Prover.Init
 func (pr *Prover) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error {
+	if len(pr.config.RpcAddrs) > 0 {
+		endpoints := []string{pr.chain.Config().RpcAddr}
+		clients := []ethClient{rpcClient{pr.chain.Client()}}
+		for _, addr := range pr.config.RpcAddrs {
+			cl, err := client.NewETHClient(addr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to %v: %v", addr, err)
+			}
+			endpoints = append(endpoints, addr)
+			clients = append(clients, rpcClient{cl})
+		}
+		qc, err := newQuorumClient(endpoints, clients, pr.config.GetRPCQuorum())
+		if err != nil {
+			return err
+		}
+		pr.client = newMeteredClient(qc, pr.chain.ChainID())
+	}
+	if pr.config.HeaderDiskCache {
+		id, err := headerCacheID(pr.config, pr.chain.Config().IBCAddress())
+		if err != nil {
+			return err
+		}
+		if err := pr.cache.enableDisk(filepath.Join(homePath, "cache", "qbft", pr.chain.ChainID(), id), pr.config.GetHeaderDiskCacheSize()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // SetRelayInfo implements Prover.SetRelayInfo
 func (pr *Prover) SetRelayInfo(path *core.PathEnd, counterparty *core.ProvableChain, counterpartyPath *core.PathEnd) error {
+	// QBFTClient.sol on an Ethereum counterparty cannot verify the validator contract proofs in the headers
+	if pr.config.UsesValidatorContract() && counterparty != nil {
+		if _, ok := counterparty.Chain.(*ethereum.Chain); ok {
+			return fmt.Errorf("the validator contract is not supported by the light client on the Ethereum counterparty %v", counterparty.ChainID())
+		}
+	}
+	pr.counterpartyPath = counterpartyPath
 	return nil
 }
 
@@ -56,42 +122,100 @@ func (pr *Prover) CreateInitialLightClientState(height exported.Height) (exporte
 		blockNumber = big.NewInt(int64(height.GetRevisionHeight()))
 	}
 
-	header, err := pr.chain.Client().HeaderByNumber(context.Background(), blockNumber)
+	ctx := context.Background()
+	header, err := pr.client.HeaderByNumber(ctx, blockNumber)
 	if err != nil {
 		return nil, nil, err
 	}
-	extra, err := parseExtraData(header.Extra)
+	h, err := pr.buildHeaderFromEthHeader(ctx, header, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	proof, err := pr.chain.Client().GetProof(pr.chain.Config().IBCAddress(), nil, big.NewInt(int64(header.Number.Int64())))
+	storageRoot, err := verifyAccountProof(header.Root, pr.chain.Config().IBCAddress(), h.header.AccountStateProof)
 	if err != nil {
 		return nil, nil, err
 	}
 	var validators [][]byte
-	for _, val := range extra.Validators {
+	for _, val := range h.validators {
 		validators = append(validators, val.Bytes())
 	}
+	clientState, err := pr.newClientState(header.Number.Int64())
+	if err != nil {
+		return nil, nil, err
+	}
+	consensusState := &ConsensusState{
+		Timestamp:  header.Time,
+		Root:       storageRoot.Bytes(),
+		Validators: validators,
+	}
+	return clientState, consensusState, nil
+}
+
+// newClientState returns the client state of the chain whose latest height is `latestHeight`
+func (pr *Prover) newClientState(latestHeight int64) (*ClientState, error) {
+	trustingPeriod, err := pr.config.GetTrustingPeriod()
+	if err != nil {
+		return nil, err
+	}
+	maxClockDrift, err := pr.config.GetMaxClockDrift()
+	if err != nil {
+		return nil, err
+	}
+	commitmentsSlot, err := pr.config.GetIBCCommitmentsSlot()
+	if err != nil {
+		return nil, err
+	}
 	var chainIDUint256 [32]byte
 	big.NewInt(int64(pr.chain.Config().EthChainId)).FillBytes(chainIDUint256[:])
 	clientState := &ClientState{
 		ChainId:         chainIDUint256[:],
 		IbcStoreAddress: pr.chain.Config().IBCAddress().Bytes(),
-		LatestHeight:    clienttypes.NewHeight(0, uint64(header.Number.Int64())),
-		TrustingPeriod:  uint64(pr.config.GetTrustingPeriod().Seconds()),
-		MaxClockDrift:   uint64(pr.config.GetMaxClockDrift().Seconds()),
+		LatestHeight:    pr.newHeight(latestHeight),
+		TrustingPeriod:  uint64(trustingPeriod.Seconds()),
+		MaxClockDrift:   uint64(maxClockDrift.Seconds()),
 	}
-	consensusState := &ConsensusState{
-		Timestamp:  header.Time,
-		Root:       proof.StorageHash[:],
-		Validators: validators,
+	if pr.config.UsesValidatorContract() {
+		clientState.ValidatorContractAddress = common.HexToAddress(pr.config.ValidatorContractAddress).Bytes()
+		clientState.ValidatorContractSlot = pr.config.ValidatorContractSlot
 	}
-	return clientState, consensusState, nil
+	// the default slot is left empty so that the client state is the same as the one before the slot was configurable
+	if commitmentsSlot != IBCCommitmentsSlot {
+		clientState.CommitmentsSlot = commitmentsSlot.Bytes()
+	}
+	return clientState, nil
 }
 
-// GetLatestFinalizedHeader implements Prover.GetLatestFinalizedHeader
+// GetLatestFinalizedHeader implements Prover.GetLatestFinalizedHeader.
+// It returns the newest header that is at least `confirmation_depth` blocks behind the head of `block_tag`
+// and is sealed by more than 2/3 of the validators.
 func (pr *Prover) GetLatestFinalizedHeader() (latestFinalizedHeader core.Header, err error) {
-	return pr.getHeader(context.TODO(), nil)
+	ctx := context.TODO()
+	head, err := pr.client.HeaderByNumber(ctx, pr.config.GetBlockTag())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the head: tag=%v: %v", pr.config.BlockTag, err)
+	}
+	depth := pr.config.ConfirmationDepth
+	if head.Number.Uint64() < depth {
+		return nil, fmt.Errorf("the head is shallower than the confirmation depth: head=%v depth=%v", head.Number, depth)
+	}
+	target := head.Number.Uint64() - depth
+	for i := uint64(0); i < maxFinalizedHeaderLookback && i <= target; i++ {
+		var header *decodedHeader
+		if i == 0 && depth == 0 {
+			// the head is already fetched
+			header, err = pr.buildHeaderFromEthHeader(ctx, head, nil)
+		} else {
+			header, err = pr.getHeader(ctx, new(big.Int).SetUint64(target-i))
+		}
+		if errors.Is(err, ErrInsufficientVoting) {
+			// skip the header that fails the seal check
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return header.header, nil
+	}
+	return nil, fmt.Errorf("no header sealed by more than 2/3 of the validators is found in %v blocks from %v", maxFinalizedHeaderLookback, target)
 }
 
 // SetupHeadersForUpdate implements Prover.SetupHeadersForUpdate
@@ -103,11 +227,16 @@ func (pr *Prover) SetupHeadersForUpdate(counterparty core.FinalityAwareChain, la
 	if err := header.ValidateBasic(); err != nil {
 		return nil, err
 	}
+	target, err := decodeHeader(header)
+	if err != nil {
+		return nil, err
+	}
 	latestHeight, err := counterparty.LatestHeight()
 	if err != nil {
 		return nil, err
 	}
-	counterpartyClientRes, err := counterparty.QueryClientState(core.NewQueryContext(context.TODO(), latestHeight))
+	queryCtx := core.NewQueryContext(context.TODO(), latestHeight)
+	counterpartyClientRes, err := counterparty.QueryClientState(queryCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -115,15 +244,66 @@ func (pr *Prover) SetupHeadersForUpdate(counterparty core.FinalityAwareChain, la
 	if err := pr.chain.Codec().UnpackAny(counterpartyClientRes.ClientState, &cs); err != nil {
 		return nil, err
 	}
-	header.TrustedHeight = cs.GetLatestHeight().(clienttypes.Height)
-	return []core.Header{header}, nil
+	clientState, ok := cs.(*ClientState)
+	if !ok {
+		return nil, fmt.Errorf("invalid client state type: %T", cs)
+	}
+	trustedHeight := clientState.LatestHeight
+	clientHeightGapGauge.Set(int64(target.height().RevisionHeight)-int64(trustedHeight.RevisionHeight), pr.clientAttrs(counterparty)...)
+	if trustedHeight.RevisionNumber != pr.config.RevisionNumber {
+		return nil, fmt.Errorf("the client must be upgraded to the current revision: client_revision=%v current_revision=%v", trustedHeight.RevisionNumber, pr.config.RevisionNumber)
+	}
+	counterpartyConsRes, err := counterparty.QueryClientConsensusState(queryCtx, trustedHeight)
+	if err != nil {
+		return nil, err
+	}
+	var cons exported.ConsensusState
+	if err := pr.chain.Codec().UnpackAny(counterpartyConsRes.ConsensusState, &cons); err != nil {
+		return nil, err
+	}
+	trustedConsState, ok := cons.(*ConsensusState)
+	if !ok {
+		return nil, fmt.Errorf("invalid consensus state type: %T", cons)
+	}
+	headers, err := pr.setupHeadersForUpdate(context.TODO(), trustedHeight, bytesToAddresses(trustedConsState.Validators), target)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the headers as the light client on the counterparty chain will do to avoid submitting invalid headers
+	now, err := counterparty.Timestamp(latestHeight)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyHeaders(clientState, trustedConsState, headers, now); err != nil {
+		return nil, err
+	}
+	return headers, nil
+}
+
+// verifyHeaders verifies the headers in order against the trusted consensus state,
+// where each header is verified against the consensus state derived from the previous one
+func verifyHeaders(clientState *ClientState, trustedConsState *ConsensusState, headers []core.Header, now time.Time) error {
+	consState := trustedConsState
+	for _, h := range headers {
+		header, ok := h.(*Header)
+		if !ok {
+			return fmt.Errorf("invalid header type: %T", h)
+		}
+		next, err := clientState.verifyHeader(consState, header, now)
+		if err != nil {
+			return fmt.Errorf("failed to verify header: height=%v trusted_height=%v: %w", header.GetHeight(), header.TrustedHeight, err)
+		}
+		consState = next
+	}
+	return nil
 }
 
 // ProveState implements Prover.ProveState
 func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) ([]byte, clienttypes.Height, error) {
 	proofHeight := int64(ctx.Height().GetRevisionHeight())
 	height := pr.newHeight(proofHeight)
-	proof, err := pr.buildStateProof([]byte(path), proofHeight)
+	proof, err := pr.buildStateProof([]byte(path), value, proofHeight)
 	return proof, height, err
 }
 
@@ -134,27 +314,118 @@ func (pr *Prover) ProveHostConsensusState(ctx core.QueryContext, height exported
 
 // CheckRefreshRequired implements Prover.CheckRefreshRequired
 func (pr *Prover) CheckRefreshRequired(counterparty core.ChainInfoICS02Querier) (bool, error) {
-	// TODO implement
-	return false, nil
+	trustingPeriod, err := pr.config.GetTrustingPeriod()
+	if err != nil {
+		return false, err
+	} else if trustingPeriod == 0 {
+		// the client never expires
+		return false, nil
+	}
+
+	cpQueryHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the latest height of the counterparty chain: %v", err)
+	}
+	cpQueryCtx := core.NewQueryContext(context.TODO(), cpQueryHeight)
+
+	resCs, err := counterparty.QueryClientState(cpQueryCtx)
+	if err != nil {
+		return false, fmt.Errorf("failed to query the client state on the counterparty chain: %v", err)
+	}
+	var cs exported.ClientState
+	if err := pr.chain.Codec().UnpackAny(resCs.ClientState, &cs); err != nil {
+		return false, fmt.Errorf("failed to unpack Any into qbft client state: %v", err)
+	}
+
+	resCons, err := counterparty.QueryClientConsensusState(cpQueryCtx, cs.GetLatestHeight())
+	if err != nil {
+		return false, fmt.Errorf("failed to query the consensus state on the counterparty chain: %v", err)
+	}
+	var cons exported.ConsensusState
+	if err := pr.chain.Codec().UnpackAny(resCons.ConsensusState, &cons); err != nil {
+		return false, fmt.Errorf("failed to unpack Any into qbft consensus state: %v", err)
+	}
+	lcLastTimestamp := time.Unix(0, int64(cons.GetTimestamp()))
+
+	selfQueryHeight, err := pr.chain.LatestHeight()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the latest height of the self chain: %v", err)
+	}
+	selfTimestamp, err := pr.chain.Timestamp(selfQueryHeight)
+	if err != nil {
+		return false, fmt.Errorf("failed to get timestamp of the self chain: %v", err)
+	}
+
+	elapsedTime := selfTimestamp.Sub(lcLastTimestamp)
+	trustingPeriodRemainingGauge.Set(int64((trustingPeriod - elapsedTime).Seconds()), pr.clientAttrs(counterparty)...)
+
+	needsRefresh := elapsedTime > durationMulByFraction(trustingPeriod, pr.config.GetRefreshThresholdRate())
+
+	return needsRefresh, nil
+}
+
+// durationMulByFraction returns `d` * `f`, which is calculated in big.Int as the product does not fit in int64 for long durations
+func durationMulByFraction(d time.Duration, f Fraction) time.Duration {
+	nsec := new(big.Int).Mul(big.NewInt(int64(d)), new(big.Int).SetUint64(f.Numerator))
+	nsec.Quo(nsec, new(big.Int).SetUint64(f.Denominator))
+	if !nsec.IsInt64() {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(nsec.Int64())
 }
 
 func (pr *Prover) newHeight(blockNumber int64) clienttypes.Height {
-	return clienttypes.NewHeight(0, uint64(blockNumber))
+	return clienttypes.NewHeight(pr.config.RevisionNumber, uint64(blockNumber))
+}
+
+// buildStateProof returns the storage proof of the commitment at `path`.
+// If `value` is empty, the proof proves the absence of the commitment.
+// It returns CommitmentMismatchError if the stored commitment is not keccak256(value), or not empty for non-membership.
+func (pr *Prover) buildStateProof(path []byte, value []byte, height int64) ([]byte, error) {
+	commitment, err := pr.queryCommitment(path, height)
+	if err != nil {
+		return nil, err
+	}
+	var expected common.Hash
+	if len(value) > 0 {
+		// IBCCommitment stores keccak256(value)
+		expected = crypto.Keccak256Hash(value)
+	}
+	if commitment.Value != expected {
+		return nil, &CommitmentMismatchError{
+			Path:     string(path),
+			Height:   height,
+			Expected: expected,
+			Actual:   commitment.Value,
+		}
+	}
+	return commitment.Proof, nil
+}
+
+// commitmentProof is the storage proof of a commitment in the IBC contract
+type commitmentProof struct {
+	StorageKey  common.Hash
+	StorageRoot common.Hash
+	Proof       []byte
+	// Value is the commitment verified with the proof, which is empty if the commitment does not exist
+	Value common.Hash
 }
 
-func (pr *Prover) buildStateProof(path []byte, height int64) ([]byte, error) {
+// queryCommitment returns the storage proof of the commitment for `path` at the height
+func (pr *Prover) queryCommitment(path []byte, height int64) (*commitmentProof, error) {
 	// calculate slot for commitment
-	storageKey := crypto.Keccak256Hash(append(
-		crypto.Keccak256Hash(path).Bytes(),
-		IBCCommitmentsSlot.Bytes()...,
-	))
+	slot, err := pr.config.GetIBCCommitmentsSlot()
+	if err != nil {
+		return nil, err
+	}
+	storageKey := commitmentStorageKey(path, slot)
 	storageKeyHex, err := storageKey.MarshalText()
 	if err != nil {
 		return nil, err
 	}
 
 	// call eth_getProof
-	stateProof, err := pr.chain.Client().GetProof(
+	stateProof, err := pr.client.GetProof(
 		pr.chain.Config().IBCAddress(),
 		[][]byte{storageKeyHex},
 		big.NewInt(height),
@@ -162,31 +433,226 @@ func (pr *Prover) buildStateProof(path []byte, height int64) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return stateProof.StorageProofRLP[0], nil
+	if len(stateProof.StorageProofRLP) != 1 {
+		return nil, fmt.Errorf("unexpected number of storage proofs: %v", len(stateProof.StorageProofRLP))
+	}
+	proof := stateProof.StorageProofRLP[0]
+
+	// obtain the storage value by verifying the proof as the light client does
+	storageValue, err := verifyStorageProof(stateProof.StorageHash, storageKey, proof)
+	if err != nil {
+		return nil, err
+	}
+	return &commitmentProof{
+		StorageKey:  storageKey,
+		StorageRoot: stateProof.StorageHash,
+		Proof:       proof,
+		Value:       storageValue,
+	}, nil
+}
+
+func (pr *Prover) getHeader(ctx context.Context, bn *big.Int) (*decodedHeader, error) {
+	ibcAddress := pr.chain.Config().IBCAddress()
+	header, proof, err := pr.client.HeaderAndProofByNumber(ctx, bn, ibcAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := verifyAccountProof(header.Root, ibcAddress, proof.AccountProofRLP); err != nil {
+		// the block of the tag may have changed between the requests in the batch, so the proof is queried again
+		proof = nil
+	}
+	return pr.buildHeaderFromEthHeader(ctx, header, proof)
 }
 
-func (pr *Prover) getHeader(ctx context.Context, bn *big.Int) (*Header, error) {
-	header, err := pr.chain.Client().HeaderByNumber(ctx, bn)
+// buildHeaderFromEthHeader returns the header of the block, which is cached by the block number and hash.
+// `proof` is the account proof of the IBC contract at the block if it has already been queried, or nil otherwise.
+func (pr *Prover) buildHeaderFromEthHeader(ctx context.Context, header *gethtypes.Header, proof *client.StateProof) (*decodedHeader, error) {
+	key := newBlockKey(header)
+	if h, ok := pr.cache.getHeader(key); ok {
+		// the seals of the cached header are recorded as if it were built, so that the liveness and the metrics cover every block
+		pr.recordSeals(key.Number, h.validators, h.header.Seals)
+		return h, nil
+	}
+	extra, err := pr.getExtraData(header)
 	if err != nil {
 		return nil, err
 	}
+	start := time.Now()
+	h, err := pr.buildHeader(ctx, header, extra, proof)
+	if err != nil {
+		return nil, err
+	}
+	headerBuildDuration.Record(ctx, durationMilliseconds(time.Since(start)), api.WithAttributes(chainAttrs(pr.chain.ChainID())...))
+	if err := pr.cache.addHeader(key, h); err != nil {
+		return nil, fmt.Errorf("failed to cache the header: %v", err)
+	}
+	return h, nil
+}
+
+// getExtraData returns the parsed extra data of the block, which is cached by the block number and hash
+func (pr *Prover) getExtraData(header *gethtypes.Header) (*ExtraData, error) {
+	key := newBlockKey(header)
+	if extra, ok := pr.cache.getExtra(key); ok {
+		return extra, nil
+	}
 	extra, err := parseExtraData(header.Extra)
 	if err != nil {
 		return nil, err
 	}
-	headerBytes, seals, err := pr.validateAndGetOrderedSeals(*header, *extra)
+	pr.cache.addExtra(key, extra)
+	return extra, nil
+}
+
+func (pr *Prover) buildHeader(ctx context.Context, header *gethtypes.Header, extra *ExtraData, proof *client.StateProof) (*decodedHeader, error) {
+	validators, validatorProof, err := pr.getValidators(ctx, header, extra)
 	if err != nil {
 		return nil, err
 	}
-	proof, err := pr.chain.Client().GetProof(pr.chain.Config().IBCAddress(), nil, big.NewInt(int64(header.Number.Int64())))
+	headerBytes, seals, err := pr.validateAndGetOrderedSeals(*header, *extra, validators)
 	if err != nil {
 		return nil, err
 	}
-	return &Header{
-		BesuHeaderRlp:     headerBytes,
-		Seals:             seals,
-		AccountStateProof: proof.AccountProofRLP,
-	}, nil
+	if proof == nil {
+		proof, err = pr.client.GetProof(pr.chain.Config().IBCAddress(), nil, header.Number)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the trusted height is determined in SetupHeadersForUpdate,
+	// but its revision number is set here so that GetHeight returns the height in the current revision
+	h, err := NewHeader(headerBytes, seals, pr.newHeight(0), proof.AccountProofRLP, validatorProof)
+	if err != nil {
+		return nil, err
+	}
+	return decodeHeader(h)
+}
+
+// setupHeadersForUpdate returns the headers required to update the client from `trustedHeight` to `target`.
+// If `target` is not sealed by enough of the trusted validators, the headers at which the validator set changed are
+// inserted before `target` so that each header is sealed by enough of the validators trusted by its predecessor.
+func (pr *Prover) setupHeadersForUpdate(ctx context.Context, trustedHeight clienttypes.Height, trustedValidators []common.Address, target *decodedHeader) ([]core.Header, error) {
+	targetHeight := target.height().RevisionHeight
+	if targetHeight <= trustedHeight.GetRevisionHeight() {
+		// the client is already up to date
+		return nil, nil
+	}
+	if ok, err := hasTrustedSeals(target, trustedValidators); err != nil {
+		return nil, err
+	} else if ok {
+		target.header.TrustedHeight = trustedHeight
+		return []core.Header{target.header}, nil
+	}
+
+	candidates, err := pr.getValidatorSetTransitions(ctx, trustedHeight.GetRevisionHeight(), targetHeight, trustedValidators)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, target)
+
+	// select the furthest header that can be verified with the trusted validators until `target` is reached
+	var headers []core.Header
+	for i := 0; i < len(candidates); {
+		next := -1
+		for j := len(candidates) - 1; j >= i; j-- {
+			ok, err := hasTrustedSeals(candidates[j], trustedValidators)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("no header in (%v, %v] is sealed by enough trusted validators", trustedHeight, candidates[i].height())
+		}
+		h := candidates[next]
+		h.header.TrustedHeight = trustedHeight
+		headers = append(headers, h.header)
+
+		trustedHeight = h.height()
+		trustedValidators = h.validators
+		i = next + 1
+	}
+	return headers, nil
+}
+
+// getValidatorSetTransitions returns the headers of the blocks in (`from`, `to`) whose validator set differs from that of the previous block.
+// `validators` is the validator set at `from`.
+func (pr *Prover) getValidatorSetTransitions(ctx context.Context, from, to uint64, validators []common.Address) ([]*decodedHeader, error) {
+	if maxRange := pr.config.GetMaxTransitionSearchRange(); to-from > maxRange {
+		return nil, fmt.Errorf("the validator set changes in (%v, %v) cannot be searched as the range exceeds max_transition_search_range=%v: update the client with an intermediate header or raise the limit", from, to, maxRange)
+	}
+	var headers []*decodedHeader
+	for start := from + 1; start < to; start += headerBatchSize {
+		var numbers []*big.Int
+		for bn := start; bn < to && bn < start+headerBatchSize; bn++ {
+			numbers = append(numbers, new(big.Int).SetUint64(bn))
+		}
+		ethHeaders, err := pr.client.HeadersByNumbers(ctx, numbers)
+		if err != nil {
+			return nil, err
+		}
+		var extras []*ExtraData
+		for _, ethHeader := range ethHeaders {
+			extra, err := pr.getExtraData(ethHeader)
+			if err != nil {
+				return nil, err
+			}
+			extras = append(extras, extra)
+		}
+		// the validator contract is called for the whole batch at once
+		validatorSets, err := pr.selectValidators(ctx, ethHeaders, extras)
+		if err != nil {
+			return nil, err
+		}
+		for i, ethHeader := range ethHeaders {
+			vals := validatorSets[i]
+			if equalValidators(validators, vals) {
+				continue
+			}
+			header, err := pr.buildHeaderFromEthHeader(ctx, ethHeader, nil)
+			if err != nil {
+				return nil, err
+			}
+			headers = append(headers, header)
+			validators = vals
+		}
+	}
+	return headers, nil
+}
+
+// hasTrustedSeals returns true if more than 1/3 of `trustedValidators` sealed the header
+func hasTrustedSeals(header *decodedHeader, trustedValidators []common.Address) (bool, error) {
+	validators := header.validators
+	if len(validators) != len(header.header.Seals) {
+		return false, fmt.Errorf("the number of seals is not equal to the number of validators: %v != %v", len(header.header.Seals), len(validators))
+	}
+	trusted := make(map[common.Address]bool)
+	for _, val := range trustedValidators {
+		trusted[val] = true
+	}
+	count := 0
+	for i, seal := range header.header.Seals {
+		if len(seal) == 0 || !trusted[validators[i]] {
+			continue
+		}
+		// count each trusted validator only once
+		trusted[validators[i]] = false
+		count++
+	}
+	return count*3 > len(trustedValidators), nil
+}
+
+func equalValidators(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 type ExtraData struct {
@@ -197,12 +663,15 @@ type ExtraData struct {
 	Seals      [][]byte
 }
 
-func (pr *Prover) validateAndGetOrderedSeals(header gethtypes.Header, extra ExtraData) ([]byte, [][]byte, error) {
+// encodeHeaderWithoutSeals returns the RLP encoding of the header whose extra data excludes the commit seals,
+// whose hash is the block hash of Besu.
+// The encoding of the extra data depends on the consensus type at the height of the header.
+func (pr *Prover) encodeHeaderWithoutSeals(header gethtypes.Header, extra ExtraData) ([]byte, error) {
 	var (
 		extraBytes []byte
 		err        error
 	)
-	if pr.config.IsIBFT2() {
+	if pr.config.IsIBFT2At(header.Number.Uint64()) {
 		extraBytes, err = rlp.EncodeToBytes([]interface{}{
 			extra.Vanity, extra.Validators, extra.Vote, extra.Round,
 		})
@@ -212,80 +681,117 @@ func (pr *Prover) validateAndGetOrderedSeals(header gethtypes.Header, extra Extr
 		})
 	}
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	header.Extra = extraBytes
-	headerBytes, err := rlp.EncodeToBytes(&header)
-	if err != nil {
-		return nil, nil, err
-	}
-	vals, err := recoverSeals(headerBytes, extra.Seals)
+	return rlp.EncodeToBytes(&header)
+}
+
+// validateAndGetOrderedSeals returns the header encoded without seals and the seals ordered by `validators`.
+// It fails if not more than 2/3 of `validators` sealed the header.
+func (pr *Prover) validateAndGetOrderedSeals(header gethtypes.Header, extra ExtraData, validators []common.Address) ([]byte, [][]byte, error) {
+	headerBytes, err := pr.encodeHeaderWithoutSeals(header, extra)
 	if err != nil {
 		return nil, nil, err
 	}
-	var orderedSeals [][]byte
-	count := 0
-	for _, val := range extra.Validators {
-		if seal, ok := vals[val]; ok {
-			count++
-			orderedSeals = append(orderedSeals, seal)
-		} else {
-			orderedSeals = append(orderedSeals, nil)
-		}
-	}
-	if threshold := len(extra.Validators) * 2 / 3; count > threshold {
+	recovered, report := recoverSeals(headerBytes, extra.Seals)
+	orderedSeals, count := orderSeals(validators, recovered, report)
+	pr.recordSeals(header.Number.Uint64(), validators, orderedSeals)
+	threshold := len(validators) * 2 / 3
+	if count > threshold {
 		return headerBytes, orderedSeals, nil
 	} else {
-		return nil, nil, fmt.Errorf("insufficient voting: %v > %v", count, threshold)
+		return nil, nil, &InsufficientVotingError{Count: count, Threshold: threshold, Report: report}
 	}
 }
 
-func recoverSeals(headerBytes []byte, seals [][]byte) (map[common.Address][]byte, error) {
-	headerHash := crypto.Keccak256(headerBytes)
-	vals := make(map[common.Address][]byte)
-	for _, seal := range seals {
-		addr, err := ecrecover(headerHash, seal[:])
-		if err != nil {
-			return nil, err
+// recordSeals records the seals of the block ordered by the validators to the liveness tracker and the metrics
+func (pr *Prover) recordSeals(number uint64, validators []common.Address, orderedSeals [][]byte) {
+	pr.liveness.Record(number, validators, orderedSeals)
+	count := 0
+	for _, seal := range orderedSeals {
+		if len(seal) > 0 {
+			count++
 		}
-		vals[addr] = seal[:]
 	}
-	return vals, nil
+	attrs := chainAttrs(pr.chain.ChainID())
+	sealCountGauge.Set(int64(count), attrs...)
+	sealThresholdGauge.Set(int64(len(validators)*2/3), attrs...)
+	validatorSetSizeGauge.Set(int64(len(validators)), attrs...)
 }
 
 func ecrecover(hash, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: length %v != %v", ErrInvalidSeal, len(sig), crypto.SignatureLength)
+	}
 	pub, err := crypto.SigToPub(hash, sig)
 	if err != nil {
-		return common.Address{}, err
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSeal, err)
 	}
 	return crypto.PubkeyToAddress(*pub), nil
 }
 
+// newExtraDataStream returns the stream of the fields of the extra data, which must be a single RLP list without trailing bytes
+func newExtraDataStream(extraBytes []byte) (*rlp.Stream, error) {
+	kind, _, rest, err := rlp.Split(extraBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExtraData, err)
+	} else if kind != rlp.List {
+		return nil, fmt.Errorf("%w: not a list", ErrInvalidExtraData)
+	} else if len(rest) > 0 {
+		return nil, fmt.Errorf("%w: %v trailing bytes", ErrInvalidExtraData, len(rest))
+	}
+	stream := rlp.NewStream(bytes.NewReader(extraBytes), uint64(len(extraBytes)))
+	if _, err := stream.List(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExtraData, err)
+	}
+	return stream, nil
+}
+
 func parseExtraData(extraBytes []byte) (*ExtraData, error) {
 	var extra ExtraData
-	r := bytes.NewReader(extraBytes)
-	stream := rlp.NewStream(r, uint64(len(extraBytes)))
-	if _, err := stream.List(); err != nil {
+	stream, err := newExtraDataStream(extraBytes)
+	if err != nil {
 		return nil, err
 	}
 	if err := stream.Decode(&extra.Vanity); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: vanity: %v", ErrInvalidExtraData, err)
 	}
 	if err := stream.Decode(&extra.Validators); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: validators: %v", ErrInvalidExtraData, err)
 	}
 	if err := stream.Decode(&extra.Vote); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: vote: %v", ErrInvalidExtraData, err)
 	}
 	if err := stream.Decode(&extra.Round); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: round: %v", ErrInvalidExtraData, err)
 	}
 	if err := stream.Decode(&extra.Seals); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: seals: %v", ErrInvalidExtraData, err)
 	}
+	// the extra data must not have trailing list elements
 	if err := stream.ListEnd(); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExtraData, err)
 	}
 
 	return &extra, nil
 }
+
+// parseValidators returns the validators in the extra data regardless of whether it contains the seals or not
+func parseValidators(extraBytes []byte) ([]common.Address, error) {
+	var (
+		vanity     []byte
+		validators []common.Address
+	)
+	stream, err := newExtraDataStream(extraBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Decode(&vanity); err != nil {
+		return nil, fmt.Errorf("%w: vanity: %v", ErrInvalidExtraData, err)
+	}
+	if err := stream.Decode(&validators); err != nil {
+		return nil, fmt.Errorf("%w: validators: %v", ErrInvalidExtraData, err)
+	}
+	return validators, nil
+}
@@ -0,0 +1,621 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/store/dbadapter"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+func BenchmarkRecoverSeals(b *testing.B) {
+	for _, n := range []int{4, 21, 64, 100} {
+		headerBytes, seals := newSealedHeaderForBench(b, n)
+		b.Run(fmt.Sprintf("validators=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				recovered, report := recoverSeals(headerBytes, seals)
+				if len(recovered) != n {
+					b.Fatalf("unexpected number of signers: %v: %v", len(recovered), report)
+				}
+			}
+		})
+	}
+}
+
+// newSealedHeaderForBench returns an RLP encoded header and its seals by `n` validators
+func newSealedHeaderForBench(b *testing.B, n int) ([]byte, [][]byte) {
+	headerBytes, err := rlp.EncodeToBytes(&gethtypes.Header{
+		Number:     big.NewInt(100),
+		Difficulty: big.NewInt(1),
+		Extra:      []byte{},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	hash := crypto.Keccak256(headerBytes)
+	var seals [][]byte
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			b.Fatal(err)
+		}
+		seal, err := crypto.Sign(hash, key)
+		if err != nil {
+			b.Fatal(err)
+		}
+		seals = append(seals, seal)
+	}
+	return headerBytes, seals
+}
+
+func TestParseExtraData(t *testing.T) {
+	for _, consensusType := range []string{QBFTConsensusType, IBFT2ConsensusType} {
+		t.Run(consensusType, func(t *testing.T) {
+			chain := newTestChain(t, newTestProverConfig(consensusType), 4)
+			header := chain.Mine(withRound(3))
+			extra, err := parseExtraData(header.Extra)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalValidators(extra.Validators, chain.validators) {
+				t.Fatalf("unexpected validators: expected=%v actual=%v", chain.validators, extra.Validators)
+			}
+			if round := new(big.Int).SetBytes(extra.Round).Uint64(); round != 3 {
+				t.Fatalf("unexpected round: %v", round)
+			}
+			if len(extra.Seals) != 4 {
+				t.Fatalf("unexpected number of seals: %v", len(extra.Seals))
+			}
+			// the header without seals must be the one sealed by the validators
+			pr := newTestProver(t, chain)
+			headerBytes, err := pr.encodeHeaderWithoutSeals(*header, *extra)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hash := crypto.Keccak256Hash(headerBytes); hash != chain.besuHash(header) {
+				t.Fatalf("unexpected hash: expected=%v actual=%v", chain.besuHash(header), hash)
+			}
+		})
+	}
+}
+
+func TestValidateAndGetOrderedSeals(t *testing.T) {
+	chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+	pr := newTestProver(t, chain)
+	vals := chain.validators
+	outsider := chain.NewKeys(1)[0]
+	cases := []struct {
+		name         string
+		opts         []testBlockOption
+		signed       int
+		insufficient bool
+		check        func(t *testing.T, report *SealReport)
+	}{
+		{name: "all", signed: 4},
+		{name: "threshold", opts: []testBlockOption{withSigners(vals[0], vals[2], vals[3])}, signed: 3},
+		{name: "reversed", opts: []testBlockOption{withSigners(vals[3], vals[2], vals[1], vals[0])}, signed: 4},
+		{name: "different round and proposer", opts: []testBlockOption{withRound(2), withProposer(outsider)}, signed: 4},
+		{
+			name:         "insufficient",
+			opts:         []testBlockOption{withSigners(vals[0], vals[1])},
+			signed:       2,
+			insufficient: true,
+			check: func(t *testing.T, report *SealReport) {
+				if len(report.MissingValidators) != 2 {
+					t.Fatalf("unexpected missing validators: %v", report)
+				}
+			},
+		},
+		{
+			name:   "non-validator",
+			opts:   []testBlockOption{withSigners(vals[0], outsider, vals[1], vals[2])},
+			signed: 3,
+			check: func(t *testing.T, report *SealReport) {
+				if len(report.NonValidatorSeals) != 1 || report.NonValidatorSeals[0].Signer != outsider {
+					t.Fatalf("unexpected non-validator seals: %v", report)
+				}
+			},
+		},
+		{
+			name: "duplicate",
+			opts: []testBlockOption{withSigners(vals[0], vals[1]), withMutateSeals(func(seals [][]byte) [][]byte {
+				return append(seals, seals[0])
+			})},
+			signed:       2,
+			insufficient: true,
+			check: func(t *testing.T, report *SealReport) {
+				if len(report.DuplicateSeals) != 1 || report.DuplicateSeals[0].Signer != vals[0] {
+					t.Fatalf("unexpected duplicate seals: %v", report)
+				}
+			},
+		},
+		{
+			name: "invalid",
+			opts: []testBlockOption{withMutateSeals(func(seals [][]byte) [][]byte {
+				seals[1] = []byte{1, 2, 3}
+				return seals
+			})},
+			signed: 3,
+			check: func(t *testing.T, report *SealReport) {
+				if len(report.InvalidSeals) != 1 || report.InvalidSeals[0].Index != 1 {
+					t.Fatalf("unexpected invalid seals: %v", report)
+				}
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			header := chain.Mine(c.opts...)
+			extra, err := parseExtraData(header.Extra)
+			if err != nil {
+				t.Fatal(err)
+			}
+			headerBytes, seals, err := pr.validateAndGetOrderedSeals(*header, *extra, vals)
+			if c.insufficient {
+				var votingErr *InsufficientVotingError
+				if !errors.As(err, &votingErr) || !errors.Is(err, ErrInsufficientVoting) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if votingErr.Count != c.signed {
+					t.Fatalf("unexpected count: expected=%v actual=%v", c.signed, votingErr.Count)
+				}
+				c.check(t, votingErr.Report)
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if err := verifyCommitSeals(vals, seals, crypto.Keccak256(headerBytes)); err != nil {
+				t.Fatal(err)
+			}
+			if c.check != nil {
+				recovered, report := recoverSeals(headerBytes, extra.Seals)
+				orderSeals(vals, recovered, report)
+				c.check(t, report)
+			}
+		})
+	}
+}
+
+func TestGetLatestFinalizedHeader(t *testing.T) {
+	chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+	pr := newTestProver(t, chain)
+	chain.MineN(3)
+	// the latest blocks are not sufficiently sealed
+	chain.MineN(2, withSigners(chain.validators[:2]...))
+	header, err := pr.GetLatestFinalizedHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height := header.GetHeight().GetRevisionHeight(); height != 3 {
+		t.Fatalf("unexpected height: %v", height)
+	}
+	if liveness := pr.Liveness().Stats(); len(liveness) != 4 || liveness[0].Missed != 2 {
+		t.Fatalf("unexpected liveness: %v", liveness)
+	}
+
+	// the seals of the cached headers are also tracked
+	cached := newTestProver(t, chain)
+	cached.cache = pr.cache
+	if _, err := cached.GetLatestFinalizedHeader(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := pr.Liveness().Stats(), cached.Liveness().Stats(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected liveness with the cache: expected=%v actual=%v", expected, actual)
+	}
+}
+
+func TestSetupHeadersForUpdate(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		config ProverConfig
+	}{
+		{name: "qbft", config: newTestProverConfig(QBFTConsensusType)},
+		{name: "ibft2 to qbft", config: func() ProverConfig {
+			config := newTestProverConfig(IBFT2ConsensusType)
+			config.ConsensusForks = []*ConsensusFork{{Block: 8, ConsensusType: QBFTConsensusType}}
+			return config
+		}()},
+		{name: "validator contract", config: func() ProverConfig {
+			config := newTestProverConfig(QBFTConsensusType)
+			config.ValidatorContractAddress = "0x0000000000000000000000000000000000008888"
+			config.ValidatorContractSlot = 3
+			return config
+		}()},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			chain := newTestChain(t, c.config, 4)
+			pr := newTestProver(t, chain)
+			chain.MineN(2)
+			trustedHeight := pr.newHeight(2)
+			clientState, trustedConsState, err := pr.CreateInitialLightClientState(trustedHeight)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// replace all the validators in two steps, so that the target cannot be verified with the trusted validators
+			old, added := chain.validators, chain.NewKeys(4)
+			next := append([]common.Address{}, old[:2]...)
+			next = append(next, added[:2]...)
+			sortAddresses(next)
+			chain.MineN(2)
+			chain.Mine(withValidators(next...))
+			chain.MineN(4)
+			chain.Mine(withValidators(added...))
+			chain.MineN(4)
+
+			target, err := pr.getHeader(context.Background(), chain.Head().Number)
+			if err != nil {
+				t.Fatal(err)
+			}
+			headers, err := pr.setupHeadersForUpdate(context.Background(), trustedHeight, bytesToAddresses(trustedConsState.(*ConsensusState).Validators), target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the target is sealed by enough of the validators after the first change
+			if len(headers) != 2 || headers[1] != target.header {
+				t.Fatalf("unexpected headers: %v", headers)
+			}
+			now := time.Unix(int64(chain.Head().Time), 0)
+			if err := verifyHeaders(clientState.(*ClientState), trustedConsState.(*ConsensusState), headers, now); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSetupHeadersForUpdateMaxTransitionSearchRange(t *testing.T) {
+	config := newTestProverConfig(QBFTConsensusType)
+	config.MaxTransitionSearchRange = 4
+	chain := newTestChain(t, config, 4)
+	pr := newTestProver(t, chain)
+	chain.MineN(2)
+	trustedHeight := pr.newHeight(2)
+	_, trustedConsState, err := pr.CreateInitialLightClientState(trustedHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the target cannot be verified with the trusted validators, which are all replaced
+	chain.Mine(withValidators(chain.NewKeys(4)...))
+	chain.MineN(4)
+
+	target, err := pr.getHeader(context.Background(), chain.Head().Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = pr.setupHeadersForUpdate(context.Background(), trustedHeight, bytesToAddresses(trustedConsState.(*ConsensusState).Validators), target)
+	if err == nil || !strings.Contains(err.Error(), "max_transition_search_range=4") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestVerifyHeaders(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		// mutate modifies the headers to be submitted
+		mutate func(headers []*Header)
+		// elapsed is the time from the last block to the verification
+		elapsed time.Duration
+		// err is the substring of the error, or empty if the headers are valid
+		err string
+	}{
+		{name: "valid"},
+		{name: "first header with seals not over 2/3", mutate: func(headers []*Header) {
+			headers[0].Seals[1], headers[0].Seals[2] = nil, nil
+		}, err: "height=0-3 trusted_height=0-2: insufficient voting"},
+		{name: "last header with seals not over 2/3", mutate: func(headers []*Header) {
+			headers[1].Seals[0], headers[1].Seals[3] = nil, nil
+		}, err: "height=0-6 trusted_height=0-3: insufficient voting"},
+		{name: "last header with a seal by another block", mutate: func(headers []*Header) {
+			headers[1].Seals[0] = headers[0].Seals[0]
+		}, err: "height=0-6 trusted_height=0-3: seal[0] is not signed by the validator"},
+		{name: "trusted consensus state expired", elapsed: 336 * time.Hour, err: "trusted consensus state is expired"},
+		{name: "header too far in the future", elapsed: -time.Minute, err: "header timestamp is too far in the future"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+			pr := newTestProver(t, chain)
+			chain.MineN(2)
+			trustedHeight := pr.newHeight(2)
+			clientState, trustedConsState, err := pr.CreateInitialLightClientState(trustedHeight)
+			if err != nil {
+				t.Fatal(err)
+			}
+			chain.MineN(4)
+			headers := []*Header{
+				newTestHeader(t, pr, 3, trustedHeight),
+				newTestHeader(t, pr, 6, pr.newHeight(3)),
+			}
+			if c.mutate != nil {
+				c.mutate(headers)
+			}
+			now := time.Unix(int64(chain.Head().Time), 0).Add(c.elapsed)
+			err = verifyHeaders(clientState.(*ClientState), trustedConsState.(*ConsensusState), []core.Header{headers[0], headers[1]}, now)
+			if c.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("unexpected error: expected=%v actual=%v", c.err, err)
+			}
+		})
+	}
+}
+
+// countingClient counts the requests of eth_call, where a batch of them counts as one request
+type countingClient struct {
+	ethClient
+	calls int
+}
+
+func (cl *countingClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	cl.calls++
+	return cl.ethClient.CallContract(ctx, msg, blockNumber)
+}
+
+func (cl *countingClient) CallContractByNumbers(ctx context.Context, msg ethereum.CallMsg, numbers []*big.Int) ([][]byte, error) {
+	cl.calls++
+	return cl.ethClient.CallContractByNumbers(ctx, msg, numbers)
+}
+
+func TestGetValidatorSetTransitionsBatchesCalls(t *testing.T) {
+	config := newTestProverConfig(QBFTConsensusType)
+	config.ValidatorContractAddress = "0x0000000000000000000000000000000000008888"
+	config.ValidatorContractSlot = 3
+	chain := newTestChain(t, config, 4)
+	chain.MineN(2)
+	validators := chain.validators
+	chain.MineN(40)
+	chain.Mine(withValidators(chain.NewKeys(4)...))
+	chain.MineN(40)
+
+	cl := &countingClient{ethClient: newTestProver(t, chain).client}
+	pr := newTestProver(t, chain).withClient(cl)
+	to := chain.Head().Number.Uint64()
+	headers, err := pr.getValidatorSetTransitions(context.Background(), 2, to, validators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the contract returns the new validator set from the block after the change
+	if len(headers) != 1 || headers[0].height().RevisionHeight != 44 {
+		t.Fatalf("unexpected transitions: %v", headers)
+	}
+	// one batch per header batch, and one call to build the header of the transition
+	if batches := (int(to-3) + headerBatchSize - 1) / headerBatchSize; cl.calls != batches+len(headers) {
+		t.Fatalf("unexpected number of eth_call requests: expected=%v actual=%v", batches+len(headers), cl.calls)
+	}
+}
+
+func TestSetRelayInfoValidatorContract(t *testing.T) {
+	config := newTestProverConfig(QBFTConsensusType)
+	config.ValidatorContractAddress = "0x0000000000000000000000000000000000008888"
+	chain := newTestChain(t, config, 4)
+	pr := newTestProver(t, chain)
+
+	// QBFTClient.sol on the Ethereum counterparty cannot verify the headers with the validator contract proofs
+	counterparty := core.NewProvableChain(newTestEthChain(t, newTestRPCServer(t, chain), testIBCAddress), nil)
+	if err := pr.SetRelayInfo(nil, counterparty, nil); err == nil || !strings.Contains(err.Error(), "validator contract is not supported") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the light client in Go supports them
+	if err := pr.SetRelayInfo(nil, core.NewProvableChain(nil, nil), nil); err != nil {
+		t.Fatal(err)
+	}
+	withoutContract := newTestProver(t, newTestChain(t, newTestProverConfig(QBFTConsensusType), 4))
+	if err := withoutContract.SetRelayInfo(nil, counterparty, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDurationMulByFraction(t *testing.T) {
+	tenYears := 10 * 365 * 24 * time.Hour
+	for _, c := range []struct {
+		d        time.Duration
+		f        Fraction
+		expected time.Duration
+	}{
+		{d: 336 * time.Hour, f: Fraction{Numerator: 1, Denominator: 2}, expected: 168 * time.Hour},
+		// the product of the nanoseconds and the numerator overflows int64
+		{d: tenYears, f: Fraction{Numerator: 999999, Denominator: 1000000}, expected: tenYears - tenYears/1000000},
+		{d: tenYears, f: Fraction{Numerator: math.MaxUint64, Denominator: math.MaxUint64}, expected: tenYears},
+	} {
+		if actual := durationMulByFraction(c.d, c.f); actual != c.expected {
+			t.Fatalf("unexpected duration of %v * %v/%v: expected=%v actual=%v", c.d, c.f.Numerator, c.f.Denominator, c.expected, actual)
+		}
+	}
+}
+
+func TestDurationMilliseconds(t *testing.T) {
+	for _, c := range []struct {
+		d        time.Duration
+		expected float64
+	}{
+		{d: 250 * time.Microsecond, expected: 0.25},
+		{d: 1500 * time.Microsecond, expected: 1.5},
+		{d: 2 * time.Second, expected: 2000},
+	} {
+		if actual := durationMilliseconds(c.d); actual != c.expected {
+			t.Fatalf("unexpected milliseconds of %v: expected=%v actual=%v", c.d, c.expected, actual)
+		}
+	}
+}
+
+func TestProveState(t *testing.T) {
+	chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+	pr := newTestProver(t, chain)
+	path, value := "commitments/ports/transfer/channels/channel-0/sequences/1", []byte("commitment")
+	absentPath := "commitments/ports/transfer/channels/channel-0/sequences/2"
+	chain.SetState(testIBCAddress, commitmentStorageKey([]byte(path), IBCCommitmentsSlot), crypto.Keccak256Hash(value))
+	header := chain.Mine()
+	lc := newTestLightClient(t, pr, header.Number.Uint64())
+	ctx := lc.ctx(time.Unix(int64(header.Time), 0))
+	queryCtx := core.NewQueryContext(context.Background(), lc.clientState.LatestHeight)
+	consState, found := GetConsensusState(lc.store, lc.cdc, lc.clientState.LatestHeight)
+	if !found {
+		t.Fatal("consensus state not found")
+	}
+	root := common.BytesToHash(consState.Root)
+
+	for _, c := range []struct {
+		name  string
+		path  string
+		value []byte
+		// mismatch is the error expected if the commitment verified locally does not match the value
+		mismatch *CommitmentMismatchError
+	}{
+		{name: "membership", path: path, value: value},
+		// the absence of a commitment is proven with an empty value
+		{name: "non-membership", path: absentPath},
+		{name: "another value", path: path, value: []byte("other"), mismatch: &CommitmentMismatchError{
+			Expected: crypto.Keccak256Hash([]byte("other")),
+			Actual:   crypto.Keccak256Hash(value),
+		}},
+		{name: "empty value of existing commitment", path: path, mismatch: &CommitmentMismatchError{
+			Actual: crypto.Keccak256Hash(value),
+		}},
+		{name: "value of absent commitment", path: absentPath, value: value, mismatch: &CommitmentMismatchError{
+			Expected: crypto.Keccak256Hash(value),
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			proof, proofHeight, err := pr.ProveState(queryCtx, c.path, c.value)
+			if c.mismatch != nil {
+				c.mismatch.Path, c.mismatch.Height = c.path, header.Number.Int64()
+				var mismatch *CommitmentMismatchError
+				if !errors.As(err, &mismatch) {
+					t.Fatalf("unexpected error: %v", err)
+				} else if !reflect.DeepEqual(mismatch, c.mismatch) {
+					t.Fatalf("unexpected mismatch: expected=%v actual=%v", c.mismatch, mismatch)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			var expected common.Hash
+			if len(c.value) > 0 {
+				expected = crypto.Keccak256Hash(c.value)
+			}
+			storageKey := commitmentStorageKey([]byte(c.path), IBCCommitmentsSlot)
+			if stored, err := verifyStorageProof(root, storageKey, proof); err != nil {
+				t.Fatal(err)
+			} else if stored != expected {
+				t.Fatalf("unexpected commitment: expected=%v actual=%v", expected, stored)
+			}
+			merklePath := commitmenttypes.NewMerklePath("ibc", c.path)
+			if len(c.value) > 0 {
+				err = lc.clientState.VerifyMembership(ctx, lc.store, lc.cdc, proofHeight, 0, 0, proof, merklePath, c.value)
+			} else {
+				err = lc.clientState.VerifyNonMembership(ctx, lc.store, lc.cdc, proofHeight, 0, 0, proof, merklePath)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// tamperingClient returns the storage proofs whose last byte is flipped
+type tamperingClient struct {
+	ethClient
+}
+
+func (cl *tamperingClient) GetProof(address common.Address, storageKeys [][]byte, blockNumber *big.Int) (*client.StateProof, error) {
+	proof, err := cl.ethClient.GetProof(address, storageKeys, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	tampered := *proof
+	tampered.StorageProofRLP = nil
+	for _, p := range proof.StorageProofRLP {
+		p = append([]byte{}, p...)
+		p[len(p)-1] ^= 1
+		tampered.StorageProofRLP = append(tampered.StorageProofRLP, p)
+	}
+	return &tampered, nil
+}
+
+func TestProveStateTamperedProof(t *testing.T) {
+	chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+	path, value := "commitments/ports/transfer/channels/channel-0/sequences/1", []byte("commitment")
+	chain.SetState(testIBCAddress, commitmentStorageKey([]byte(path), IBCCommitmentsSlot), crypto.Keccak256Hash(value))
+	header := chain.Mine()
+	pr := newTestProver(t, chain)
+	pr = pr.withClient(&tamperingClient{ethClient: pr.client})
+
+	// the proof that the light client would reject is not returned
+	queryCtx := core.NewQueryContext(context.Background(), pr.newHeight(header.Number.Int64()))
+	var mismatch *CommitmentMismatchError
+	if _, _, err := pr.ProveState(queryCtx, path, value); err == nil || errors.As(err, &mismatch) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestProveStateCommitmentsSlot(t *testing.T) {
+	config := newTestProverConfig(QBFTConsensusType)
+	// the slot is written without the 0x prefix as IBCCommitmentsSlot
+	slot := crypto.Keccak256Hash([]byte("commitments"))
+	config.IbcCommitmentsSlot = hex.EncodeToString(slot.Bytes())
+	chain := newTestChain(t, config, 4)
+	pr := newTestProver(t, chain)
+	path, value := "commitments/ports/transfer/channels/channel-0/sequences/1", []byte("commitment")
+	chain.SetState(testIBCAddress, commitmentStorageKey([]byte(path), slot), crypto.Keccak256Hash(value))
+	header := chain.Mine()
+
+	height := pr.newHeight(header.Number.Int64())
+	cs, cons, err := pr.CreateInitialLightClientState(height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientState := cs.(*ClientState)
+	if !bytes.Equal(clientState.CommitmentsSlot, slot.Bytes()) {
+		t.Fatalf("unexpected commitments slot: expected=%v actual=%x", slot, clientState.CommitmentsSlot)
+	}
+	proof, proofHeight, err := pr.ProveState(core.NewQueryContext(context.Background(), height), path, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := codectypes.NewInterfaceRegistry()
+	Module{}.RegisterInterfaces(registry)
+	cdc := codec.NewProtoCodec(registry)
+	clientStore := dbadapter.Store{DB: dbm.NewMemDB()}
+	setConsensusState(clientStore, cdc, cons.(*ConsensusState), proofHeight)
+	merklePath := commitmenttypes.NewMerklePath("ibc", path)
+	if err := clientState.VerifyMembership(sdk.Context{}, clientStore, cdc, proofHeight, 0, 0, proof, merklePath, value); err != nil {
+		t.Fatal(err)
+	}
+	// the absence at the configured slot is proven with an empty value
+	absentPath := "commitments/ports/transfer/channels/channel-0/sequences/2"
+	absenceProof, _, err := pr.ProveState(core.NewQueryContext(context.Background(), height), absentPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := clientState.VerifyNonMembership(sdk.Context{}, clientStore, cdc, proofHeight, 0, 0, absenceProof, commitmenttypes.NewMerklePath("ibc", absentPath)); err != nil {
+		t.Fatal(err)
+	}
+	// the client with the default slot looks up another storage key
+	defaultClientState := *clientState
+	defaultClientState.CommitmentsSlot = nil
+	if err := defaultClientState.VerifyMembership(sdk.Context{}, clientStore, cdc, proofHeight, 0, 0, proof, merklePath, value); err == nil {
+		t.Fatal("the proof at the configured slot is accepted by the client with the default slot")
+	}
+}
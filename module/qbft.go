@@ -1,10 +1,19 @@
 package module
 
 import (
+	"bytes"
+	"fmt"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
 	storetypes "cosmossdk.io/store/types"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const QBFT_CLIENT_TYPE = "hb-qbft"
@@ -20,71 +29,223 @@ func (cs *ClientState) GetLatestHeight() exported.Height {
 }
 
 func (cs *ClientState) Validate() error {
+	if len(cs.ChainId) != 32 {
+		return fmt.Errorf("chain id must be 32 bytes: length=%v", len(cs.ChainId))
+	}
+	if len(cs.IbcStoreAddress) != common.AddressLength {
+		return fmt.Errorf("ibc store address must be %v bytes: length=%v", common.AddressLength, len(cs.IbcStoreAddress))
+	}
+	if cs.LatestHeight.RevisionHeight == 0 {
+		return fmt.Errorf("latest height must not be zero")
+	}
+	if len(cs.ValidatorContractAddress) != 0 && len(cs.ValidatorContractAddress) != common.AddressLength {
+		return fmt.Errorf("validator contract address must be %v bytes: length=%v", common.AddressLength, len(cs.ValidatorContractAddress))
+	}
+	if len(cs.CommitmentsSlot) != 0 && len(cs.CommitmentsSlot) != common.HashLength {
+		return fmt.Errorf("commitments slot must be %v bytes: length=%v", common.HashLength, len(cs.CommitmentsSlot))
+	}
 	return nil
 }
 
-func (cs *ClientState) Status(ctx sdk.Context, clientStore storetypes.KVStore, cdc codec.BinaryCodec) exported.Status {
-	panic("not implemented")
+// commitmentsSlot returns the storage slot of the commitments mapping in the IBC contract
+func (cs *ClientState) commitmentsSlot() common.Hash {
+	if len(cs.CommitmentsSlot) == 0 {
+		return IBCCommitmentsSlot
+	}
+	return common.BytesToHash(cs.CommitmentsSlot)
 }
 
+// Status returns the status of the client.
+// The client is frozen if a misbehaviour has been submitted,
+// and is expired if the latest consensus state has passed the trusting period.
+func (cs *ClientState) Status(ctx sdk.Context, clientStore storetypes.KVStore, cdc codec.BinaryCodec) exported.Status {
+	if !cs.FrozenHeight.IsZero() {
+		return exported.Frozen
+	}
+	consState, found := GetConsensusState(clientStore, cdc, cs.GetLatestHeight())
+	if !found {
+		// if the client state does not have an associated consensus state for its latest height
+		// then it must be expired
+		return exported.Expired
+	}
+	if cs.isExpired(consState.Timestamp, ctx.BlockTime()) {
+		return exported.Expired
+	}
+	return exported.Active
+}
+
+// ExportMetadata exports the processed times and heights of the consensus states
 func (cs *ClientState) ExportMetadata(clientStore storetypes.KVStore) []exported.GenesisMetadata {
-	panic("not implemented")
-}
-
+	var gm []exported.GenesisMetadata
+	iterateConsensusMetadata(clientStore, func(key, val []byte) bool {
+		gm = append(gm, clienttypes.NewGenesisMetadata(key, val))
+		return false
+	})
+	if len(gm) == 0 {
+		return nil
+	}
+	return gm
+}
+
+// ZeroCustomFields returns a copy of the client state with the client customizable fields zeroed out
 func (cs *ClientState) ZeroCustomFields() exported.ClientState {
-	panic("not implemented")
+	return &ClientState{
+		ChainId:                  cs.ChainId,
+		IbcStoreAddress:          cs.IbcStoreAddress,
+		LatestHeight:             cs.LatestHeight,
+		ValidatorContractAddress: cs.ValidatorContractAddress,
+		ValidatorContractSlot:    cs.ValidatorContractSlot,
+		CommitmentsSlot:          cs.CommitmentsSlot,
+	}
 }
 
+// GetTimestampAtHeight returns the timestamp in nanoseconds of the consensus state at the given height
 func (cs *ClientState) GetTimestampAtHeight(ctx sdk.Context, clientStore storetypes.KVStore, cdc codec.BinaryCodec, height exported.Height) (uint64, error) {
-	panic("not implemented")
+	consState, found := GetConsensusState(clientStore, cdc, height)
+	if !found {
+		return 0, errorsmod.Wrapf(clienttypes.ErrConsensusStateNotFound, "height (%s)", height)
+	}
+	return consState.GetTimestamp(), nil
 }
 
+// Initialize checks that the initial consensus state is a qbft consensus state and
+// sets the client state, consensus state and associated metadata in the provided client store
 func (cs *ClientState) Initialize(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, consensusState exported.ConsensusState) error {
-	panic("not implemented")
+	consState, ok := consensusState.(*ConsensusState)
+	if !ok {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "invalid initial consensus state. expected type: %T, got: %T", &ConsensusState{}, consensusState)
+	}
+	setClientState(clientStore, cdc, cs)
+	setConsensusState(clientStore, cdc, consState, cs.GetLatestHeight())
+	setConsensusMetadata(ctx, clientStore, cs.GetLatestHeight())
+	return nil
 }
 
+// VerifyMembership verifies the storage proof that the commitment of `value` is stored at `path` in the IBC contract
 func (cs *ClientState) VerifyMembership(ctx sdk.Context, clientStore storetypes.KVStore, cdc codec.BinaryCodec, height exported.Height, delayTimePeriod uint64, delayBlockPeriod uint64, proof []byte, path exported.Path, value []byte) error {
-	panic("not implemented")
+	storageValue, err := cs.verifyCommitment(ctx, clientStore, cdc, height, delayTimePeriod, delayBlockPeriod, proof, path)
+	if err != nil {
+		return errorsmod.Wrap(clienttypes.ErrFailedMembershipVerification, err.Error())
+	}
+	if expected := crypto.Keccak256Hash(value); storageValue != expected {
+		return errorsmod.Wrapf(clienttypes.ErrFailedMembershipVerification, "commitment mismatch: expected=%v actual=%v", expected, storageValue)
+	}
+	return nil
 }
 
+// VerifyNonMembership verifies the storage proof that no commitment is stored at `path` in the IBC contract
 func (cs *ClientState) VerifyNonMembership(ctx sdk.Context, clientStore storetypes.KVStore, cdc codec.BinaryCodec, height exported.Height, delayTimePeriod uint64, delayBlockPeriod uint64, proof []byte, path exported.Path) error {
-	panic("not implemented")
-}
-
-func (cs *ClientState) VerifyClientMessage(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) error {
-	panic("not implemented")
-}
-
-func (cs *ClientState) CheckForMisbehaviour(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) bool {
-	panic("not implemented")
-}
-
-func (cs *ClientState) UpdateStateOnMisbehaviour(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) {
-	panic("not implemented")
+	storageValue, err := cs.verifyCommitment(ctx, clientStore, cdc, height, delayTimePeriod, delayBlockPeriod, proof, path)
+	if err != nil {
+		return errorsmod.Wrap(clienttypes.ErrFailedNonMembershipVerification, err.Error())
+	}
+	if storageValue != (common.Hash{}) {
+		return errorsmod.Wrapf(clienttypes.ErrFailedNonMembershipVerification, "commitment exists: value=%v", storageValue)
+	}
+	return nil
 }
 
-func (cs *ClientState) UpdateState(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) []exported.Height {
-	panic("not implemented")
+// verifyCommitment verifies the storage proof of the commitment at `path` against the consensus state at `height`
+// and returns the proven storage value
+func (cs *ClientState) verifyCommitment(ctx sdk.Context, clientStore storetypes.KVStore, cdc codec.BinaryCodec, height exported.Height, delayTimePeriod uint64, delayBlockPeriod uint64, proof []byte, path exported.Path) (common.Hash, error) {
+	if cs.GetLatestHeight().LT(height) {
+		return common.Hash{}, errorsmod.Wrapf(clienttypes.ErrInvalidHeight, "client state height < proof height (%d < %d), please ensure the client has been updated", cs.GetLatestHeight(), height)
+	}
+	if err := verifyDelayPeriodPassed(ctx, clientStore, height, delayTimePeriod, delayBlockPeriod); err != nil {
+		return common.Hash{}, err
+	}
+	merklePath, ok := path.(commitmenttypes.MerklePath)
+	if !ok {
+		return common.Hash{}, errorsmod.Wrapf(clienttypes.ErrInvalidClientType, "expected %T, got %T", commitmenttypes.MerklePath{}, path)
+	}
+	// the key path consists of the commitment prefix and the ICS-24 path,
+	// and the prefix is not a part of the storage key in the IBC contract
+	if len(merklePath.KeyPath) != 2 {
+		return common.Hash{}, errorsmod.Wrapf(commitmenttypes.ErrInvalidProof, "key path length must be 2: length=%v", len(merklePath.KeyPath))
+	}
+	consState, found := GetConsensusState(clientStore, cdc, height)
+	if !found {
+		return common.Hash{}, errorsmod.Wrap(clienttypes.ErrConsensusStateNotFound, "please ensure the proof was constructed against a height that exists on the client")
+	}
+	storageKey := commitmentStorageKey([]byte(merklePath.KeyPath[1]), cs.commitmentsSlot())
+	value, err := verifyStorageProof(common.BytesToHash(consState.Root), storageKey, proof)
+	if err != nil {
+		return common.Hash{}, errorsmod.Wrap(commitmenttypes.ErrInvalidProof, err.Error())
+	}
+	return value, nil
+}
+
+// verifyDelayPeriodPassed will ensure that at least delayTimePeriod amount of time and delayBlockPeriod number of blocks have passed
+// since consensus state was submitted before allowing verification to continue
+func verifyDelayPeriodPassed(ctx sdk.Context, clientStore storetypes.KVStore, proofHeight exported.Height, delayTimePeriod, delayBlockPeriod uint64) error {
+	if delayTimePeriod != 0 {
+		processedTime, ok := GetProcessedTime(clientStore, proofHeight)
+		if !ok {
+			return fmt.Errorf("processed time not found for height: %s", proofHeight)
+		}
+		currentTimestamp := uint64(ctx.BlockTime().UnixNano())
+		if validTime := processedTime + delayTimePeriod; currentTimestamp < validTime {
+			return fmt.Errorf("cannot verify packet until time: %d, current time: %d", validTime, currentTimestamp)
+		}
+	}
+	if delayBlockPeriod != 0 {
+		processedHeight, ok := GetProcessedHeight(clientStore, proofHeight)
+		if !ok {
+			return fmt.Errorf("processed height not found for height: %s", proofHeight)
+		}
+		currentHeight := clienttypes.GetSelfHeight(ctx)
+		validHeight := clienttypes.NewHeight(processedHeight.GetRevisionNumber(), processedHeight.GetRevisionHeight()+delayBlockPeriod)
+		if currentHeight.LT(validHeight) {
+			return fmt.Errorf("cannot verify packet until height: %s, current height: %s", validHeight, currentHeight)
+		}
+	}
+	return nil
 }
 
+// CheckSubstituteAndUpdateState replaces the client state and the latest consensus state with the ones of the substitute client
+// if both clients track the same IBC contract on the same chain
 func (cs *ClientState) CheckSubstituteAndUpdateState(ctx sdk.Context, cdc codec.BinaryCodec, subjectClientStore, substituteClientStore storetypes.KVStore, substituteClient exported.ClientState) error {
-	panic("not implemented")
-}
-
-func (cs *ClientState) VerifyUpgradeAndUpdateState(ctx sdk.Context, cdc codec.BinaryCodec, store storetypes.KVStore, newClient exported.ClientState, newConsState exported.ConsensusState, proofUpgradeClient, proofUpgradeConsState []byte) error {
-	panic("not implemented")
+	substituteClientState, ok := substituteClient.(*ClientState)
+	if !ok {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "expected type %T, got %T", &ClientState{}, substituteClient)
+	}
+	if !bytes.Equal(cs.ChainId, substituteClientState.ChainId) || !bytes.Equal(cs.IbcStoreAddress, substituteClientState.IbcStoreAddress) {
+		return errorsmod.Wrap(clienttypes.ErrInvalidSubstitute, "subject client state does not match substitute client state")
+	}
+
+	height := substituteClientState.GetLatestHeight()
+	consState, found := GetConsensusState(substituteClientStore, cdc, height)
+	if !found {
+		return errorsmod.Wrap(clienttypes.ErrConsensusStateNotFound, "unable to retrieve latest consensus state for substitute client")
+	}
+	setConsensusState(subjectClientStore, cdc, consState, height)
+	setConsensusMetadata(ctx, subjectClientStore, height)
+	setClientState(subjectClientStore, cdc, substituteClientState)
+	return nil
 }
 
 var _ exported.ConsensusState = (*ConsensusState)(nil)
 
 func (cs *ConsensusState) ClientType() string {
-	panic("not implemented")
+	return QBFT_CLIENT_TYPE
 }
 
+// GetTimestamp returns the timestamp of the consensus state in nanoseconds
 func (cs *ConsensusState) GetTimestamp() uint64 {
-	panic("not implemented")
+	return cs.Timestamp * uint64(time.Second)
 }
 
 func (cs *ConsensusState) ValidateBasic() error {
-	panic("not implemented")
+	if len(cs.Root) != common.HashLength {
+		return fmt.Errorf("root must be %v bytes: length=%v", common.HashLength, len(cs.Root))
+	}
+	if len(cs.Validators) == 0 {
+		return fmt.Errorf("validators must not be empty")
+	}
+	for i, val := range cs.Validators {
+		if len(val) != common.AddressLength {
+			return fmt.Errorf("validator[%v] must be %v bytes: length=%v", i, common.AddressLength, len(val))
+		}
+	}
+	return nil
 }
@@ -35,6 +35,17 @@ type ClientState struct {
 	TrustingPeriod uint64 `protobuf:"varint,4,opt,name=trusting_period,json=trustingPeriod,proto3" json:"trusting_period,omitempty"`
 	// duration in seconds
 	MaxClockDrift uint64 `protobuf:"varint,5,opt,name=max_clock_drift,json=maxClockDrift,proto3" json:"max_clock_drift,omitempty"`
+	// address of the validator contract
+	// if this is set, the validator set is verified with the validator contract proof in the header
+	ValidatorContractAddress []byte `protobuf:"bytes,6,opt,name=validator_contract_address,json=validatorContractAddress,proto3" json:"validator_contract_address,omitempty"`
+	// storage slot of the validator array in the validator contract
+	ValidatorContractSlot uint64 `protobuf:"varint,7,opt,name=validator_contract_slot,json=validatorContractSlot,proto3" json:"validator_contract_slot,omitempty"`
+	// height at which the client was frozen due to a misbehaviour
+	// the client is not frozen if this is zero
+	FrozenHeight types.Height `protobuf:"bytes,8,opt,name=frozen_height,json=frozenHeight,proto3" json:"frozen_height"`
+	// storage slot of the commitments mapping in the IBC contract
+	// if this is empty, the slot of yui-ibc-solidity is used
+	CommitmentsSlot []byte `protobuf:"bytes,9,opt,name=commitments_slot,json=commitmentsSlot,proto3" json:"commitments_slot,omitempty"`
 }
 
 func (m *ClientState) Reset()         { *m = ClientState{} }
@@ -115,6 +126,8 @@ type Header struct {
 	Seals             [][]byte     `protobuf:"bytes,2,rep,name=seals,proto3" json:"seals,omitempty"`
 	TrustedHeight     types.Height `protobuf:"bytes,3,opt,name=trusted_height,json=trustedHeight,proto3" json:"trusted_height"`
 	AccountStateProof []byte       `protobuf:"bytes,4,opt,name=account_state_proof,json=accountStateProof,proto3" json:"account_state_proof,omitempty"`
+	// this is set only if the validator set is managed by a validator contract
+	ValidatorContractProof *ValidatorContractProof `protobuf:"bytes,5,opt,name=validator_contract_proof,json=validatorContractProof,proto3" json:"validator_contract_proof,omitempty"`
 }
 
 func (m *Header) Reset()         { *m = Header{} }
@@ -150,10 +163,95 @@ func (m *Header) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Header proto.InternalMessageInfo
 
+type ValidatorContractProof struct {
+	// validator set stored in the validator contract at the parent block, which seals the header
+	Validators [][]byte `protobuf:"bytes,1,rep,name=validators,proto3" json:"validators,omitempty"`
+	// RLP encoded parent header, excluding its seals, whose state root the proofs are verified against
+	ParentHeaderRlp []byte `protobuf:"bytes,2,opt,name=parent_header_rlp,json=parentHeaderRlp,proto3" json:"parent_header_rlp,omitempty"`
+	// RLP encoded account proof of the validator contract
+	AccountProof []byte `protobuf:"bytes,3,opt,name=account_proof,json=accountProof,proto3" json:"account_proof,omitempty"`
+	// RLP encoded storage proofs of the length of the validator array followed by each of its elements
+	StorageProofs [][]byte `protobuf:"bytes,4,rep,name=storage_proofs,json=storageProofs,proto3" json:"storage_proofs,omitempty"`
+}
+
+func (m *ValidatorContractProof) Reset()         { *m = ValidatorContractProof{} }
+func (m *ValidatorContractProof) String() string { return proto.CompactTextString(m) }
+func (*ValidatorContractProof) ProtoMessage()    {}
+func (*ValidatorContractProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b2e4ed46cb60dd4a, []int{3}
+}
+func (m *ValidatorContractProof) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ValidatorContractProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ValidatorContractProof.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ValidatorContractProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidatorContractProof.Merge(m, src)
+}
+func (m *ValidatorContractProof) XXX_Size() int {
+	return m.Size()
+}
+func (m *ValidatorContractProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidatorContractProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidatorContractProof proto.InternalMessageInfo
+
+// Misbehaviour is the evidence that two conflicting headers at the same height are sealed by the validators
+type Misbehaviour struct {
+	Header1 *Header `protobuf:"bytes,1,opt,name=header_1,json=header1,proto3" json:"header_1,omitempty"`
+	Header2 *Header `protobuf:"bytes,2,opt,name=header_2,json=header2,proto3" json:"header_2,omitempty"`
+}
+
+func (m *Misbehaviour) Reset()         { *m = Misbehaviour{} }
+func (m *Misbehaviour) String() string { return proto.CompactTextString(m) }
+func (*Misbehaviour) ProtoMessage()    {}
+func (*Misbehaviour) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b2e4ed46cb60dd4a, []int{4}
+}
+func (m *Misbehaviour) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Misbehaviour) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Misbehaviour.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Misbehaviour) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Misbehaviour.Merge(m, src)
+}
+func (m *Misbehaviour) XXX_Size() int {
+	return m.Size()
+}
+func (m *Misbehaviour) XXX_DiscardUnknown() {
+	xxx_messageInfo_Misbehaviour.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Misbehaviour proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*ClientState)(nil), "ibc.lightclients.qbft.v1.ClientState")
 	proto.RegisterType((*ConsensusState)(nil), "ibc.lightclients.qbft.v1.ConsensusState")
 	proto.RegisterType((*Header)(nil), "ibc.lightclients.qbft.v1.Header")
+	proto.RegisterType((*ValidatorContractProof)(nil), "ibc.lightclients.qbft.v1.ValidatorContractProof")
+	proto.RegisterType((*Misbehaviour)(nil), "ibc.lightclients.qbft.v1.Misbehaviour")
 }
 
 func init() {
@@ -161,39 +259,53 @@ func init() {
 }
 
 var fileDescriptor_b2e4ed46cb60dd4a = []byte{
-	// 511 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0xcf, 0x6e, 0xd3, 0x40,
-	0x10, 0xc6, 0x63, 0x9a, 0x16, 0xd8, 0x26, 0x8d, 0xba, 0xf4, 0x60, 0x22, 0xe4, 0x44, 0x41, 0x82,
-	0x08, 0x29, 0xb6, 0x02, 0xbc, 0x00, 0x0d, 0x88, 0x72, 0xab, 0xdc, 0x1b, 0x97, 0xd5, 0xfe, 0x8b,
-	0xb3, 0x62, 0xed, 0x35, 0xbb, 0x6b, 0xab, 0xbc, 0x05, 0x6f, 0xc4, 0x35, 0xc7, 0x1e, 0x39, 0x21,
-	0x48, 0xae, 0x3c, 0x04, 0xda, 0x5d, 0x03, 0x15, 0x37, 0x4e, 0x9e, 0xf9, 0xcd, 0x37, 0xb6, 0xbf,
-	0x4f, 0x03, 0x1e, 0x0b, 0x42, 0x33, 0x29, 0x8a, 0x8d, 0xa5, 0x52, 0xf0, 0xca, 0x9a, 0xec, 0x23,
-	0x59, 0xdb, 0xac, 0x5d, 0xfa, 0x67, 0x5a, 0x6b, 0x65, 0x15, 0x8c, 0x05, 0xa1, 0xe9, 0x6d, 0x51,
-	0xea, 0x87, 0xed, 0x72, 0x3c, 0x71, 0xeb, 0x54, 0x69, 0x9e, 0x85, 0x89, 0x5b, 0x0c, 0x55, 0x58,
-	0x1d, 0x4f, 0x0a, 0xa5, 0x0a, 0xc9, 0x33, 0xdf, 0x91, 0x66, 0x9d, 0x59, 0x51, 0x72, 0x63, 0x71,
-	0x59, 0x77, 0x82, 0xe4, 0x5f, 0x01, 0x6b, 0x34, 0xb6, 0x42, 0x55, 0xdd, 0xfc, 0xac, 0x50, 0x85,
-	0xf2, 0x65, 0xe6, 0xaa, 0x40, 0x67, 0x3f, 0x23, 0x70, 0xbc, 0xf2, 0xdf, 0xb9, 0xb2, 0xd8, 0x72,
-	0xf8, 0x10, 0xdc, 0xa3, 0x1b, 0x2c, 0x2a, 0x24, 0x58, 0x1c, 0x4d, 0xa3, 0xf9, 0x20, 0xbf, 0xeb,
-	0xfb, 0x77, 0x0c, 0x3e, 0x03, 0xa7, 0x82, 0x50, 0x64, 0xac, 0xd2, 0x1c, 0x61, 0xc6, 0x34, 0x37,
-	0x26, 0xbe, 0xe3, 0x35, 0x23, 0x41, 0xe8, 0x95, 0xe3, 0xaf, 0x02, 0x86, 0x6f, 0xc0, 0x50, 0x62,
-	0xcb, 0x8d, 0x45, 0x1b, 0xee, 0xec, 0xc6, 0x07, 0xd3, 0x68, 0x7e, 0xfc, 0x7c, 0x9c, 0xba, 0x00,
-	0x9c, 0xcd, 0xb4, 0x33, 0xd7, 0x2e, 0xd3, 0x0b, 0xaf, 0x38, 0xef, 0x6f, 0xbf, 0x4d, 0x7a, 0xf9,
-	0x20, 0xac, 0x05, 0x06, 0x9f, 0x82, 0x91, 0xd5, 0x8d, 0xb1, 0xa2, 0x2a, 0x50, 0xcd, 0xb5, 0x50,
-	0x2c, 0xee, 0x4f, 0xa3, 0x79, 0x3f, 0x3f, 0xf9, 0x8d, 0x2f, 0x3d, 0x85, 0x4f, 0xc0, 0xa8, 0xc4,
-	0xd7, 0x88, 0x4a, 0x45, 0x3f, 0x20, 0xa6, 0xc5, 0xda, 0xc6, 0x87, 0x5e, 0x38, 0x2c, 0xf1, 0xf5,
-	0xca, 0xd1, 0xd7, 0x0e, 0xce, 0x08, 0x38, 0x59, 0xa9, 0xca, 0xf0, 0xca, 0x34, 0x26, 0x18, 0x7e,
-	0x04, 0xee, 0xff, 0x49, 0xd2, 0x3b, 0xee, 0xe7, 0x7f, 0x01, 0x84, 0xa0, 0xaf, 0x95, 0xb2, 0x9d,
-	0x4d, 0x5f, 0xc3, 0x04, 0x80, 0x16, 0x4b, 0xc1, 0xb0, 0x55, 0xda, 0xc4, 0x07, 0xd3, 0x83, 0xf9,
-	0x20, 0xbf, 0x45, 0x66, 0x5f, 0x22, 0x70, 0x74, 0xc1, 0x31, 0xe3, 0xda, 0xfd, 0x16, 0xe1, 0xa6,
-	0x41, 0x1b, 0xdf, 0x22, 0x2d, 0xeb, 0x2e, 0xd4, 0xa1, 0xc3, 0x41, 0x94, 0xcb, 0x1a, 0x9e, 0x81,
-	0x43, 0xc3, 0xb1, 0x74, 0x71, 0xba, 0xb7, 0x85, 0x06, 0xbe, 0x05, 0xc1, 0x26, 0x67, 0xff, 0x9b,
-	0xe2, 0xb0, 0xdb, 0xeb, 0x62, 0x4c, 0xc1, 0x03, 0x4c, 0xa9, 0x6a, 0x2a, 0x8b, 0x8c, 0x33, 0x8d,
-	0x6a, 0xad, 0xd4, 0xda, 0x47, 0x39, 0xc8, 0x4f, 0xbb, 0x91, 0x8f, 0xe3, 0xd2, 0x0d, 0xce, 0xf3,
-	0xed, 0x8f, 0xa4, 0xb7, 0xdd, 0x25, 0xd1, 0xcd, 0x2e, 0x89, 0xbe, 0xef, 0x92, 0xe8, 0xf3, 0x3e,
-	0xe9, 0xdd, 0xec, 0x93, 0xde, 0xd7, 0x7d, 0xd2, 0x7b, 0xff, 0xb2, 0x10, 0x76, 0xd3, 0x90, 0x94,
-	0xaa, 0x32, 0x63, 0xd8, 0x62, 0x7f, 0x1f, 0x12, 0x93, 0xcc, 0x79, 0x5a, 0x08, 0x42, 0x17, 0x9a,
-	0x4b, 0xfc, 0x69, 0x51, 0x6b, 0xd5, 0x72, 0x9d, 0x95, 0x8a, 0x35, 0x92, 0x93, 0x23, 0x7f, 0x6f,
-	0x2f, 0x7e, 0x05, 0x00, 0x00, 0xff, 0xff, 0x1b, 0x89, 0x30, 0xd7, 0x28, 0x03, 0x00, 0x00,
+	// 736 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x41, 0x6f, 0xd3, 0x30,
+	0x14, 0x6e, 0xd6, 0x6e, 0xdd, 0xdc, 0x76, 0x63, 0x61, 0x8c, 0x50, 0xa1, 0xb4, 0xea, 0x04, 0x14,
+	0xa4, 0x25, 0xb4, 0x20, 0x4e, 0x5c, 0x58, 0x41, 0x8c, 0x03, 0xd2, 0x94, 0x49, 0x1c, 0xb8, 0x44,
+	0x8e, 0xe3, 0xa6, 0x86, 0x24, 0x0e, 0xb6, 0x53, 0x0d, 0x7e, 0x05, 0xbf, 0x81, 0x2b, 0x17, 0x7e,
+	0xc6, 0x8e, 0x3b, 0x70, 0xe0, 0x34, 0x41, 0xf7, 0x47, 0x90, 0xed, 0xb4, 0xab, 0x4a, 0x27, 0xb1,
+	0x53, 0xec, 0xef, 0x7d, 0x9f, 0x9f, 0xdf, 0xf7, 0x9e, 0x03, 0xf6, 0x48, 0x80, 0xdc, 0x98, 0x44,
+	0x23, 0x81, 0x62, 0x82, 0x53, 0xc1, 0xdd, 0x4f, 0xc1, 0x50, 0xb8, 0xe3, 0x9e, 0xfa, 0x3a, 0x19,
+	0xa3, 0x82, 0x9a, 0x16, 0x09, 0x90, 0x33, 0x4f, 0x72, 0x54, 0x70, 0xdc, 0x6b, 0xb6, 0xa4, 0x1c,
+	0x51, 0x86, 0x5d, 0x1d, 0x91, 0x42, 0xbd, 0xd2, 0xd2, 0x66, 0x2b, 0xa2, 0x34, 0x8a, 0xb1, 0xab,
+	0x76, 0x41, 0x3e, 0x74, 0x05, 0x49, 0x30, 0x17, 0x30, 0xc9, 0x0a, 0x82, 0xbd, 0x48, 0x08, 0x73,
+	0x06, 0x05, 0xa1, 0x69, 0x11, 0xdf, 0x89, 0x68, 0x44, 0xd5, 0xd2, 0x95, 0x2b, 0x8d, 0x76, 0x7e,
+	0x96, 0x41, 0x6d, 0xa0, 0xf2, 0x1c, 0x0b, 0x28, 0xb0, 0x79, 0x07, 0xac, 0xa3, 0x11, 0x24, 0xa9,
+	0x4f, 0x42, 0xcb, 0x68, 0x1b, 0xdd, 0xba, 0x57, 0x55, 0xfb, 0x37, 0xa1, 0xf9, 0x08, 0x6c, 0x93,
+	0x00, 0xf9, 0x5c, 0x50, 0x86, 0x7d, 0x18, 0x86, 0x0c, 0x73, 0x6e, 0xad, 0x28, 0xce, 0x16, 0x09,
+	0xd0, 0xb1, 0xc4, 0x5f, 0x68, 0xd8, 0x7c, 0x05, 0x1a, 0x31, 0x14, 0x98, 0x0b, 0x7f, 0x84, 0x65,
+	0xb9, 0x56, 0xb9, 0x6d, 0x74, 0x6b, 0xfd, 0xa6, 0x23, 0x0d, 0x90, 0x65, 0x3a, 0x45, 0x71, 0xe3,
+	0x9e, 0x73, 0xa8, 0x18, 0x07, 0x95, 0xd3, 0xf3, 0x56, 0xc9, 0xab, 0x6b, 0x99, 0xc6, 0xcc, 0x07,
+	0x60, 0x4b, 0xb0, 0x9c, 0x0b, 0x92, 0x46, 0x7e, 0x86, 0x19, 0xa1, 0xa1, 0x55, 0x69, 0x1b, 0xdd,
+	0x8a, 0xb7, 0x39, 0x85, 0x8f, 0x14, 0x6a, 0xde, 0x07, 0x5b, 0x09, 0x3c, 0xf1, 0x51, 0x4c, 0xd1,
+	0x47, 0x3f, 0x64, 0x64, 0x28, 0xac, 0x55, 0x45, 0x6c, 0x24, 0xf0, 0x64, 0x20, 0xd1, 0x97, 0x12,
+	0x34, 0x9f, 0x83, 0xe6, 0x18, 0xc6, 0x24, 0x84, 0x82, 0x32, 0x1f, 0xd1, 0x54, 0x30, 0x88, 0xc4,
+	0xac, 0x98, 0x35, 0x55, 0x8c, 0x35, 0x63, 0x0c, 0x0a, 0xc2, 0xb4, 0xaa, 0x67, 0xe0, 0xf6, 0x12,
+	0x35, 0x8f, 0xa9, 0xb0, 0xaa, 0x2a, 0xdb, 0xad, 0x7f, 0xa4, 0xc7, 0x31, 0x15, 0xd2, 0x8d, 0x21,
+	0xa3, 0x5f, 0x70, 0x3a, 0x75, 0x63, 0xfd, 0x7f, 0xdd, 0xd0, 0xb2, 0xc2, 0x8d, 0x87, 0xe0, 0x06,
+	0xa2, 0x49, 0x42, 0x44, 0x22, 0x47, 0x47, 0xe7, 0xdd, 0xd0, 0xfe, 0xcf, 0xe1, 0x32, 0x63, 0x27,
+	0x00, 0x9b, 0x03, 0x9a, 0x72, 0x9c, 0xf2, 0x9c, 0xeb, 0xc6, 0xde, 0x05, 0x1b, 0xb3, 0x89, 0x51,
+	0x9d, 0xad, 0x78, 0x97, 0x80, 0x69, 0x82, 0x0a, 0xa3, 0x54, 0x14, 0xed, 0x54, 0x6b, 0xd3, 0x06,
+	0x60, 0x56, 0x0e, 0xb7, 0xca, 0xed, 0x72, 0xb7, 0xee, 0xcd, 0x21, 0x9d, 0xef, 0x2b, 0x60, 0xed,
+	0x10, 0xc3, 0x10, 0x33, 0x69, 0x7f, 0x80, 0x79, 0xee, 0x8f, 0xd4, 0xd6, 0x67, 0x71, 0x56, 0x0c,
+	0x4f, 0x43, 0xc2, 0x9a, 0xe4, 0xc5, 0x99, 0xb9, 0x03, 0x56, 0x39, 0x86, 0xb1, 0x1c, 0x1b, 0x79,
+	0x9a, 0xde, 0x98, 0xaf, 0x81, 0x6e, 0x27, 0x0e, 0xaf, 0x3b, 0x2d, 0x8d, 0x42, 0x57, 0x18, 0xe4,
+	0x80, 0x9b, 0x10, 0x21, 0x9a, 0xa7, 0xc2, 0xe7, 0xb2, 0x68, 0x3f, 0x63, 0x94, 0x0e, 0xd5, 0xc8,
+	0xd4, 0xbd, 0xed, 0x22, 0xa4, 0xec, 0x38, 0x92, 0x01, 0xf3, 0x03, 0xb0, 0x96, 0xf4, 0x53, 0x8b,
+	0x56, 0xd5, 0x15, 0x1e, 0x3b, 0x57, 0xbd, 0x58, 0xe7, 0xdd, 0x62, 0xab, 0xd5, 0x99, 0xde, 0xee,
+	0x78, 0x29, 0xde, 0xf9, 0x61, 0x80, 0xdd, 0xe5, 0x92, 0x05, 0xa3, 0x8d, 0x45, 0xa3, 0xe5, 0xc3,
+	0xcb, 0x20, 0xc3, 0xa9, 0x98, 0xf7, 0xb7, 0x78, 0x78, 0x3a, 0x70, 0xe9, 0xf0, 0x1e, 0x68, 0x4c,
+	0x2d, 0xd0, 0x75, 0x94, 0x15, 0xaf, 0x5e, 0x80, 0x3a, 0xe1, 0x3d, 0xb0, 0x29, 0x5f, 0x31, 0x8c,
+	0x0a, 0x87, 0xb8, 0x55, 0x51, 0x49, 0x1b, 0x05, 0xaa, 0x58, 0xbc, 0xf3, 0xcd, 0x00, 0xf5, 0xb7,
+	0x84, 0x07, 0x78, 0x04, 0xc7, 0x84, 0xe6, 0xcc, 0x3c, 0x04, 0xeb, 0xc5, 0x0d, 0x7a, 0xaa, 0xbf,
+	0xb5, 0x7e, 0xfb, 0x6a, 0x7f, 0xf4, 0x9d, 0x0e, 0x6a, 0x93, 0xf3, 0x56, 0x55, 0xaf, 0x7b, 0x5e,
+	0x55, 0xcb, 0x7b, 0x73, 0x27, 0xf5, 0xad, 0x95, 0xeb, 0x9f, 0xd4, 0x9f, 0x9e, 0xd4, 0x3f, 0xf0,
+	0x4e, 0xff, 0xd8, 0xa5, 0xd3, 0x89, 0x6d, 0x9c, 0x4d, 0x6c, 0xe3, 0xf7, 0xc4, 0x36, 0xbe, 0x5e,
+	0xd8, 0xa5, 0xb3, 0x0b, 0xbb, 0xf4, 0xeb, 0xc2, 0x2e, 0xbd, 0x7f, 0x1a, 0x11, 0x31, 0xca, 0x03,
+	0x07, 0xd1, 0xc4, 0x0d, 0xa1, 0x80, 0xea, 0x5f, 0x16, 0xc3, 0xc0, 0x95, 0x73, 0xb9, 0x4f, 0x02,
+	0xb4, 0xcf, 0x70, 0x0c, 0x3f, 0xef, 0x67, 0x8c, 0x8e, 0x31, 0x73, 0x13, 0x1a, 0xe6, 0x31, 0x0e,
+	0xd6, 0xd4, 0xbf, 0xf1, 0xc9, 0xdf, 0x01, 0x00, 0x7a, 0x3b, 0xba, 0xd1, 0xd4, 0x05, 0x00, 0x00,
 }
 
 func (m *ClientState) Marshal() (dAtA []byte, err error) {
@@ -216,6 +328,35 @@ func (m *ClientState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.CommitmentsSlot) > 0 {
+		i -= len(m.CommitmentsSlot)
+		copy(dAtA[i:], m.CommitmentsSlot)
+		i = encodeVarintQbft(dAtA, i, uint64(len(m.CommitmentsSlot)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	{
+		size, err := m.FrozenHeight.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQbft(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x42
+	if m.ValidatorContractSlot != 0 {
+		i = encodeVarintQbft(dAtA, i, uint64(m.ValidatorContractSlot))
+		i--
+		dAtA[i] = 0x38
+	}
+	if len(m.ValidatorContractAddress) > 0 {
+		i -= len(m.ValidatorContractAddress)
+		copy(dAtA[i:], m.ValidatorContractAddress)
+		i = encodeVarintQbft(dAtA, i, uint64(len(m.ValidatorContractAddress)))
+		i--
+		dAtA[i] = 0x32
+	}
 	if m.MaxClockDrift != 0 {
 		i = encodeVarintQbft(dAtA, i, uint64(m.MaxClockDrift))
 		i--
@@ -317,6 +458,18 @@ func (m *Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ValidatorContractProof != nil {
+		{
+			size, err := m.ValidatorContractProof.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQbft(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
 	if len(m.AccountStateProof) > 0 {
 		i -= len(m.AccountStateProof)
 		copy(dAtA[i:], m.AccountStateProof)
@@ -353,6 +506,108 @@ func (m *Header) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ValidatorContractProof) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ValidatorContractProof) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ValidatorContractProof) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.StorageProofs) > 0 {
+		for iNdEx := len(m.StorageProofs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.StorageProofs[iNdEx])
+			copy(dAtA[i:], m.StorageProofs[iNdEx])
+			i = encodeVarintQbft(dAtA, i, uint64(len(m.StorageProofs[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.AccountProof) > 0 {
+		i -= len(m.AccountProof)
+		copy(dAtA[i:], m.AccountProof)
+		i = encodeVarintQbft(dAtA, i, uint64(len(m.AccountProof)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ParentHeaderRlp) > 0 {
+		i -= len(m.ParentHeaderRlp)
+		copy(dAtA[i:], m.ParentHeaderRlp)
+		i = encodeVarintQbft(dAtA, i, uint64(len(m.ParentHeaderRlp)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Validators) > 0 {
+		for iNdEx := len(m.Validators) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Validators[iNdEx])
+			copy(dAtA[i:], m.Validators[iNdEx])
+			i = encodeVarintQbft(dAtA, i, uint64(len(m.Validators[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Misbehaviour) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Misbehaviour) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Misbehaviour) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Header2 != nil {
+		{
+			size, err := m.Header2.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQbft(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Header1 != nil {
+		{
+			size, err := m.Header1.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQbft(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintQbft(dAtA []byte, offset int, v uint64) int {
 	offset -= sovQbft(v)
 	base := offset
@@ -386,6 +641,19 @@ func (m *ClientState) Size() (n int) {
 	if m.MaxClockDrift != 0 {
 		n += 1 + sovQbft(uint64(m.MaxClockDrift))
 	}
+	l = len(m.ValidatorContractAddress)
+	if l > 0 {
+		n += 1 + l + sovQbft(uint64(l))
+	}
+	if m.ValidatorContractSlot != 0 {
+		n += 1 + sovQbft(uint64(m.ValidatorContractSlot))
+	}
+	l = m.FrozenHeight.Size()
+	n += 1 + l + sovQbft(uint64(l))
+	l = len(m.CommitmentsSlot)
+	if l > 0 {
+		n += 1 + l + sovQbft(uint64(l))
+	}
 	return n
 }
 
@@ -433,6 +701,56 @@ func (m *Header) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovQbft(uint64(l))
 	}
+	if m.ValidatorContractProof != nil {
+		l = m.ValidatorContractProof.Size()
+		n += 1 + l + sovQbft(uint64(l))
+	}
+	return n
+}
+
+func (m *ValidatorContractProof) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Validators) > 0 {
+		for _, b := range m.Validators {
+			l = len(b)
+			n += 1 + l + sovQbft(uint64(l))
+		}
+	}
+	l = len(m.ParentHeaderRlp)
+	if l > 0 {
+		n += 1 + l + sovQbft(uint64(l))
+	}
+	l = len(m.AccountProof)
+	if l > 0 {
+		n += 1 + l + sovQbft(uint64(l))
+	}
+	if len(m.StorageProofs) > 0 {
+		for _, b := range m.StorageProofs {
+			l = len(b)
+			n += 1 + l + sovQbft(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Misbehaviour) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Header1 != nil {
+		l = m.Header1.Size()
+		n += 1 + l + sovQbft(uint64(l))
+	}
+	if m.Header2 != nil {
+		l = m.Header2.Size()
+		n += 1 + l + sovQbft(uint64(l))
+	}
 	return n
 }
 
@@ -610,61 +928,45 @@ func (m *ClientState) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipQbft(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorContractAddress", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthQbft
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			if byteLen < 0 {
+				return ErrInvalidLengthQbft
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *ConsensusState) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowQbft
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.ValidatorContractAddress = append(m.ValidatorContractAddress[:0], dAtA[iNdEx:postIndex]...)
+			if m.ValidatorContractAddress == nil {
+				m.ValidatorContractAddress = []byte{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: ConsensusState: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ConsensusState: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorContractSlot", wireType)
 			}
-			m.Timestamp = 0
+			m.ValidatorContractSlot = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQbft
@@ -674,16 +976,16 @@ func (m *ConsensusState) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Timestamp |= uint64(b&0x7F) << shift
+				m.ValidatorContractSlot |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Root", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FrozenHeight", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQbft
@@ -693,23 +995,159 @@ func (m *ConsensusState) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthQbft
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthQbft
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Root = append(m.Root[:0], dAtA[iNdEx:postIndex]...)
-			if m.Root == nil {
+			if err := m.FrozenHeight.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitmentsSlot", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CommitmentsSlot = append(m.CommitmentsSlot[:0], dAtA[iNdEx:postIndex]...)
+			if m.CommitmentsSlot == nil {
+				m.CommitmentsSlot = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQbft(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConsensusState) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQbft
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConsensusState: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConsensusState: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Root", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Root = append(m.Root[:0], dAtA[iNdEx:postIndex]...)
+			if m.Root == nil {
 				m.Root = []byte{}
 			}
 			iNdEx = postIndex
@@ -928,6 +1366,346 @@ func (m *Header) Unmarshal(dAtA []byte) error {
 				m.AccountStateProof = []byte{}
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorContractProof", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ValidatorContractProof == nil {
+				m.ValidatorContractProof = &ValidatorContractProof{}
+			}
+			if err := m.ValidatorContractProof.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQbft(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ValidatorContractProof) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQbft
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ValidatorContractProof: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ValidatorContractProof: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Validators", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Validators = append(m.Validators, make([]byte, postIndex-iNdEx))
+			copy(m.Validators[len(m.Validators)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ParentHeaderRlp", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ParentHeaderRlp = append(m.ParentHeaderRlp[:0], dAtA[iNdEx:postIndex]...)
+			if m.ParentHeaderRlp == nil {
+				m.ParentHeaderRlp = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AccountProof", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AccountProof = append(m.AccountProof[:0], dAtA[iNdEx:postIndex]...)
+			if m.AccountProof == nil {
+				m.AccountProof = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StorageProofs", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StorageProofs = append(m.StorageProofs, make([]byte, postIndex-iNdEx))
+			copy(m.StorageProofs[len(m.StorageProofs)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQbft(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Misbehaviour) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQbft
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Misbehaviour: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Misbehaviour: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header1", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header1 == nil {
+				m.Header1 = &Header{}
+			}
+			if err := m.Header1.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header2", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQbft
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQbft
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQbft
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header2 == nil {
+				m.Header2 = &Header{}
+			}
+			if err := m.Header2.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQbft(dAtA[iNdEx:])
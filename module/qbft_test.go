@@ -0,0 +1,110 @@
+package module
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+func TestStatus(t *testing.T) {
+	chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+	pr := newTestProver(t, chain)
+	chain.MineN(2)
+	lc := newTestLightClient(t, pr, 2)
+	timestamp := time.Unix(int64(chain.Head().Time), 0)
+	trustingPeriod := time.Duration(lc.clientState.TrustingPeriod) * time.Second
+
+	status := func(now time.Time) exported.Status {
+		return lc.clientState.Status(lc.ctx(now), lc.store, lc.cdc)
+	}
+	if s := status(timestamp.Add(trustingPeriod - time.Second)); s != exported.Active {
+		t.Fatalf("unexpected status within the trusting period: %v", s)
+	}
+	if s := status(timestamp.Add(trustingPeriod)); s != exported.Expired {
+		t.Fatalf("unexpected status after the trusting period: %v", s)
+	}
+
+	// the client without the trusting period never expires
+	noTrustingPeriod := *lc.clientState
+	noTrustingPeriod.TrustingPeriod = 0
+	if s := noTrustingPeriod.Status(lc.ctx(timestamp.Add(100*trustingPeriod)), lc.store, lc.cdc); s != exported.Active {
+		t.Fatalf("unexpected status without the trusting period: %v", s)
+	}
+
+	// the client without the consensus state at the latest height is expired
+	missing := *lc.clientState
+	missing.LatestHeight = pr.newHeight(3)
+	if s := missing.Status(lc.ctx(timestamp), lc.store, lc.cdc); s != exported.Expired {
+		t.Fatalf("unexpected status without the latest consensus state: %v", s)
+	}
+
+	lc.clientState.UpdateStateOnMisbehaviour(lc.ctx(timestamp), lc.cdc, lc.store, &Misbehaviour{})
+	if s := status(timestamp); s != exported.Frozen {
+		t.Fatalf("unexpected status after the misbehaviour: %v", s)
+	}
+	if !lc.clientState.FrozenHeight.EQ(FrozenHeight) {
+		t.Fatalf("unexpected frozen height: %v", lc.clientState.FrozenHeight)
+	}
+}
+
+func TestVerifyMembership(t *testing.T) {
+	chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+	pr := newTestProver(t, chain)
+	path, value := "commitments/ports/transfer/channels/channel-0/sequences/1", []byte("commitment")
+	absentPath := "commitments/ports/transfer/channels/channel-0/sequences/2"
+	chain.SetState(testIBCAddress, commitmentStorageKey([]byte(path), IBCCommitmentsSlot), crypto.Keccak256Hash(value))
+	chain.MineN(2)
+	lc := newTestLightClient(t, pr, 1)
+	height := lc.clientState.LatestHeight
+	ctx := lc.ctx(time.Unix(int64(chain.Head().Time), 0))
+
+	queryCtx := core.NewQueryContext(context.Background(), height)
+	proof, proofHeight, err := pr.ProveState(queryCtx, path, value)
+	if err != nil {
+		t.Fatal(err)
+	} else if !proofHeight.EQ(height) {
+		t.Fatalf("unexpected proof height: expected=%v actual=%v", height, proofHeight)
+	}
+	absenceProof, _, err := pr.ProveState(queryCtx, absentPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append([]byte{}, proof...)
+	tampered[len(tampered)-1] ^= 1
+
+	verifyMembership := func(height exported.Height, proof []byte, path string, value []byte) error {
+		return lc.clientState.VerifyMembership(ctx, lc.store, lc.cdc, height, 0, 0, proof, commitmenttypes.NewMerklePath("ibc", path), value)
+	}
+	verifyNonMembership := func(height exported.Height, proof []byte, path string) error {
+		return lc.clientState.VerifyNonMembership(ctx, lc.store, lc.cdc, height, 0, 0, proof, commitmenttypes.NewMerklePath("ibc", path))
+	}
+	for _, c := range []struct {
+		name   string
+		verify func() error
+		valid  bool
+	}{
+		{name: "membership", verify: func() error { return verifyMembership(height, proof, path, value) }, valid: true},
+		{name: "membership of another value", verify: func() error { return verifyMembership(height, proof, path, []byte("other")) }},
+		{name: "membership at another path", verify: func() error { return verifyMembership(height, proof, absentPath, value) }},
+		{name: "membership with tampered proof", verify: func() error { return verifyMembership(height, tampered, path, value) }},
+		{name: "membership with absence proof", verify: func() error { return verifyMembership(height, absenceProof, absentPath, value) }},
+		{name: "membership above latest height", verify: func() error { return verifyMembership(pr.newHeight(2), proof, path, value) }},
+		{name: "non-membership", verify: func() error { return verifyNonMembership(height, absenceProof, absentPath) }, valid: true},
+		{name: "non-membership of existing commitment", verify: func() error { return verifyNonMembership(height, proof, path) }},
+		{name: "non-membership with tampered proof", verify: func() error { return verifyNonMembership(height, tampered, path) }},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.verify()
+			if c.valid && err != nil {
+				t.Fatal(err)
+			} else if !c.valid && err == nil {
+				t.Fatal("invalid proof is accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package module
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SealReport describes the seals of a header that were not counted as votes, and the validators that did not seal it
+type SealReport struct {
+	InvalidSeals      []InvalidSeal      `json:"invalid_seals,omitempty"`
+	DuplicateSeals    []DuplicateSeal    `json:"duplicate_seals,omitempty"`
+	NonValidatorSeals []NonValidatorSeal `json:"non_validator_seals,omitempty"`
+	MissingValidators []common.Address   `json:"missing_validators,omitempty"`
+}
+
+// InvalidSeal is a seal whose signer cannot be recovered
+type InvalidSeal struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// DuplicateSeal is a seal by the signer of a preceding seal
+type DuplicateSeal struct {
+	Index      int            `json:"index"`
+	Signer     common.Address `json:"signer"`
+	FirstIndex int            `json:"first_index"`
+}
+
+// NonValidatorSeal is a seal by a signer that is not in the validator set
+type NonValidatorSeal struct {
+	Index  int            `json:"index"`
+	Signer common.Address `json:"signer"`
+}
+
+func (r *SealReport) String() string {
+	var parts []string
+	if len(r.InvalidSeals) > 0 {
+		var ss []string
+		for _, s := range r.InvalidSeals {
+			ss = append(ss, fmt.Sprintf("#%v: %v", s.Index, s.Reason))
+		}
+		parts = append(parts, fmt.Sprintf("invalid seals=[%v]", strings.Join(ss, ", ")))
+	}
+	if len(r.DuplicateSeals) > 0 {
+		var ss []string
+		for _, s := range r.DuplicateSeals {
+			ss = append(ss, fmt.Sprintf("#%v by %v (first #%v)", s.Index, s.Signer, s.FirstIndex))
+		}
+		parts = append(parts, fmt.Sprintf("duplicate seals=[%v]", strings.Join(ss, ", ")))
+	}
+	if len(r.NonValidatorSeals) > 0 {
+		var ss []string
+		for _, s := range r.NonValidatorSeals {
+			ss = append(ss, fmt.Sprintf("#%v by %v", s.Index, s.Signer))
+		}
+		parts = append(parts, fmt.Sprintf("non-validator seals=[%v]", strings.Join(ss, ", ")))
+	}
+	if len(r.MissingValidators) > 0 {
+		var ss []string
+		for _, val := range r.MissingValidators {
+			ss = append(ss, val.Hex())
+		}
+		parts = append(parts, fmt.Sprintf("missing validators=[%v]", strings.Join(ss, ", ")))
+	}
+	if len(parts) == 0 {
+		return "no problematic seals"
+	}
+	return strings.Join(parts, " ")
+}
+
+// recoveredSeal is a seal whose signer is recovered
+type recoveredSeal struct {
+	Index  int
+	Signer common.Address
+	Seal   []byte
+}
+
+// recoverSeals recovers the signers of the seals concurrently.
+// The seals that cannot be recovered and the duplicate seals of the same signer are skipped and recorded in the report.
+func recoverSeals(headerBytes []byte, seals [][]byte) ([]recoveredSeal, *SealReport) {
+	headerHash := crypto.Keccak256(headerBytes)
+	addrs := make([]common.Address, len(seals))
+	errs := make([]error, len(seals))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(seals) {
+		workers = len(seals)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		// each worker recovers the seals at the indices congruent to `w` modulo `workers`
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(seals); i += workers {
+				addrs[i], errs[i] = ecrecover(headerHash, seals[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var (
+		recovered []recoveredSeal
+		report    SealReport
+		first     = make(map[common.Address]int)
+	)
+	for i, seal := range seals {
+		if errs[i] != nil {
+			report.InvalidSeals = append(report.InvalidSeals, InvalidSeal{Index: i, Reason: errs[i].Error()})
+			continue
+		}
+		if j, ok := first[addrs[i]]; ok {
+			report.DuplicateSeals = append(report.DuplicateSeals, DuplicateSeal{Index: i, Signer: addrs[i], FirstIndex: j})
+			continue
+		}
+		first[addrs[i]] = i
+		recovered = append(recovered, recoveredSeal{Index: i, Signer: addrs[i], Seal: seal})
+	}
+	return recovered, &report
+}
+
+// orderSeals returns the seals ordered by `validators`, where the seals of the validators that did not seal are nil,
+// and the number of the validators that sealed.
+// The seals by non-validators and the validators that did not seal are recorded in the report.
+func orderSeals(validators []common.Address, recovered []recoveredSeal, report *SealReport) ([][]byte, int) {
+	bySigner := make(map[common.Address]recoveredSeal)
+	for _, seal := range recovered {
+		bySigner[seal.Signer] = seal
+	}
+	orderedSeals := make([][]byte, len(validators))
+	count := 0
+	for i, val := range validators {
+		seal, ok := bySigner[val]
+		if !ok {
+			report.MissingValidators = append(report.MissingValidators, val)
+			continue
+		}
+		orderedSeals[i] = seal.Seal
+		delete(bySigner, val)
+		count++
+	}
+	// the remaining seals are not by the validators
+	for _, seal := range recovered {
+		if _, ok := bySigner[seal.Signer]; ok {
+			report.NonValidatorSeals = append(report.NonValidatorSeals, NonValidatorSeal{Index: seal.Index, Signer: seal.Signer})
+		}
+	}
+	return orderedSeals, count
+}
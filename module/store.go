@@ -0,0 +1,115 @@
+package module
+
+import (
+	"strings"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+)
+
+var (
+	// KeyProcessedTime is appended to consensus state key to store the processed time
+	KeyProcessedTime = []byte("/processedTime")
+	// KeyProcessedHeight is appended to consensus state key to store the processed height
+	KeyProcessedHeight = []byte("/processedHeight")
+)
+
+// setClientState stores the client state
+func setClientState(clientStore storetypes.KVStore, cdc codec.BinaryCodec, clientState *ClientState) {
+	key := host.ClientStateKey()
+	val := clienttypes.MustMarshalClientState(cdc, clientState)
+	clientStore.Set(key, val)
+}
+
+// setConsensusState stores the consensus state at the given height
+func setConsensusState(clientStore storetypes.KVStore, cdc codec.BinaryCodec, consensusState *ConsensusState, height exported.Height) {
+	key := host.ConsensusStateKey(height)
+	val := clienttypes.MustMarshalConsensusState(cdc, consensusState)
+	clientStore.Set(key, val)
+}
+
+// GetConsensusState retrieves the consensus state from the client prefixed store.
+// If the ConsensusState does not exist in state for the provided height a nil value and false boolean flag is returned
+func GetConsensusState(clientStore storetypes.KVStore, cdc codec.BinaryCodec, height exported.Height) (*ConsensusState, bool) {
+	bz := clientStore.Get(host.ConsensusStateKey(height))
+	if len(bz) == 0 {
+		return nil, false
+	}
+	consensusState, ok := clienttypes.MustUnmarshalConsensusState(cdc, bz).(*ConsensusState)
+	if !ok {
+		return nil, false
+	}
+	return consensusState, true
+}
+
+// setConsensusMetadata stores the time and the height at which the consensus state at `height` was processed
+func setConsensusMetadata(ctx sdk.Context, clientStore storetypes.KVStore, height exported.Height) {
+	SetProcessedTime(clientStore, height, uint64(ctx.BlockTime().UnixNano()))
+	SetProcessedHeight(clientStore, height, clienttypes.GetSelfHeight(ctx))
+}
+
+// ProcessedTimeKey returns the key under which the processed time will be stored in the client store
+func ProcessedTimeKey(height exported.Height) []byte {
+	return append(host.ConsensusStateKey(height), KeyProcessedTime...)
+}
+
+// SetProcessedTime stores the time (in nanoseconds) at which the consensus state at `height` was processed
+func SetProcessedTime(clientStore storetypes.KVStore, height exported.Height, timeNs uint64) {
+	clientStore.Set(ProcessedTimeKey(height), sdk.Uint64ToBigEndian(timeNs))
+}
+
+// GetProcessedTime gets the time (in nanoseconds) at which the consensus state at `height` was processed
+func GetProcessedTime(clientStore storetypes.KVStore, height exported.Height) (uint64, bool) {
+	bz := clientStore.Get(ProcessedTimeKey(height))
+	if len(bz) == 0 {
+		return 0, false
+	}
+	return sdk.BigEndianToUint64(bz), true
+}
+
+// ProcessedHeightKey returns the key under which the processed height will be stored in the client store
+func ProcessedHeightKey(height exported.Height) []byte {
+	return append(host.ConsensusStateKey(height), KeyProcessedHeight...)
+}
+
+// SetProcessedHeight stores the height at which the consensus state at `consHeight` was processed
+func SetProcessedHeight(clientStore storetypes.KVStore, consHeight, processedHeight exported.Height) {
+	clientStore.Set(ProcessedHeightKey(consHeight), []byte(processedHeight.String()))
+}
+
+// GetProcessedHeight gets the height at which the consensus state at `height` was processed
+func GetProcessedHeight(clientStore storetypes.KVStore, height exported.Height) (exported.Height, bool) {
+	bz := clientStore.Get(ProcessedHeightKey(height))
+	if len(bz) == 0 {
+		return nil, false
+	}
+	processedHeight, err := clienttypes.ParseHeight(string(bz))
+	if err != nil {
+		return nil, false
+	}
+	return processedHeight, true
+}
+
+// iterateConsensusMetadata iterates through the processed times and heights in the client store and applies the callback.
+// If the cb returns true, then iterator will close and stop.
+func iterateConsensusMetadata(clientStore storetypes.KVStore, cb func(key, val []byte) bool) {
+	iterator := storetypes.KVStorePrefixIterator(clientStore, []byte(host.KeyConsensusStatePrefix))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		// metadata keys have the format: "consensusStates/<height>/processedTime"
+		keySplit := strings.Split(string(iterator.Key()), "/")
+		if len(keySplit) != 3 {
+			continue
+		}
+		if keySplit[2] != "processedTime" && keySplit[2] != "processedHeight" {
+			continue
+		}
+		if cb(iterator.Key(), iterator.Value()) {
+			break
+		}
+	}
+}
@@ -0,0 +1,430 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/relay/ethereum"
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/relay/ethereum/signers/hd"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	testEthChainID   = 2018
+	testGenesisTime  = 1700000000
+	testBlockPeriod  = 2
+	testMnemonic     = "math razor capable expose worth grape metal sunset metal sudden usage scheme"
+	testHDWalletPath = "m/44'/60'/0'/0/0"
+)
+
+var (
+	testIBCAddress = common.HexToAddress("0x702E40245797c5a2108A566b3CE2Bf14Bc6aF841")
+	// testMixDigest is the mix digest of the blocks of QBFT and IBFT 2.0
+	testMixDigest = common.HexToHash("0x63746963616c2062797a616e74696e65206661756c7420746f6c6572616e6365")
+)
+
+// testChain is an in-memory chain that produces blocks sealed by the keys it generated,
+// whose consensus type at each height follows the prover config
+type testChain struct {
+	mu sync.Mutex
+
+	config ProverConfig
+	keys   map[common.Address]*ecdsa.PrivateKey
+	// validators is the validator set in the extra data of the next block, or in the validator contract
+	validators []common.Address
+
+	headers []*gethtypes.Header
+	states  []*testState
+	// pending is the state of the next block
+	pending *testState
+}
+
+// testBlockOptions are the options to produce a block of testChain
+type testBlockOptions struct {
+	round      uint32
+	proposer   *common.Address
+	validators []common.Address
+	// signers are the keys that seal the block in order, which are the validators of the block if nil
+	signers []common.Address
+	// mutateSeals modifies the seals before they are set to the extra data
+	mutateSeals func(seals [][]byte) [][]byte
+}
+
+type testBlockOption func(*testBlockOptions)
+
+// withRound sets the round in which the block is committed
+func withRound(round uint32) testBlockOption {
+	return func(opts *testBlockOptions) { opts.round = round }
+}
+
+// withProposer sets the coinbase of the block, which is selected by round robin by default
+func withProposer(proposer common.Address) testBlockOption {
+	return func(opts *testBlockOptions) { opts.proposer = &proposer }
+}
+
+// withValidators changes the validator set from the block,
+// or from the next block if the validator contract is used as the contract returns the new set at the block
+func withValidators(validators ...common.Address) testBlockOption {
+	return func(opts *testBlockOptions) { opts.validators = validators }
+}
+
+// withSigners sets the keys that seal the block in order
+func withSigners(signers ...common.Address) testBlockOption {
+	return func(opts *testBlockOptions) { opts.signers = signers }
+}
+
+// withMutateSeals modifies the seals of the block, e.g. to duplicate or corrupt them
+func withMutateSeals(f func(seals [][]byte) [][]byte) testBlockOption {
+	return func(opts *testBlockOptions) { opts.mutateSeals = f }
+}
+
+// newTestChain returns a chain whose genesis block is validated by `numValidators` validators
+func newTestChain(t testing.TB, config ProverConfig, numValidators int) *testChain {
+	c := &testChain{
+		config:  config,
+		keys:    make(map[common.Address]*ecdsa.PrivateKey),
+		pending: newTestState(),
+	}
+	c.validators = c.NewKeys(numValidators)
+	// the IBC contract must exist so that its account proof can be obtained
+	c.pending.account(testIBCAddress).nonce = 1
+	if config.UsesValidatorContract() {
+		c.setContractValidators(c.validators)
+	}
+	c.Mine()
+	return c
+}
+
+// NewKeys generates `n` deterministic keys and returns their addresses in ascending order as Besu sorts validators
+func (c *testChain) NewKeys(n int) []common.Address {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var addrs []common.Address
+	for i := 0; i < n; i++ {
+		seed := crypto.Keccak256([]byte(fmt.Sprintf("test key %d", len(c.keys))))
+		key, err := crypto.ToECDSA(seed)
+		if err != nil {
+			panic(err)
+		}
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		c.keys[addr] = key
+		addrs = append(addrs, addr)
+	}
+	sortAddresses(addrs)
+	return addrs
+}
+
+func sortAddresses(addrs []common.Address) {
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0 })
+}
+
+// SetState sets the storage value of the account in the next block
+func (c *testChain) SetState(addr common.Address, key, value common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending.SetState(addr, key, value)
+}
+
+// setContractValidators writes the validator set to the storage of the validator contract in the next block
+func (c *testChain) setContractValidators(validators []common.Address) {
+	contract := common.HexToAddress(c.config.ValidatorContractAddress)
+	slot := c.config.ValidatorContractSlot
+	keys := validatorStorageKeys(slot, len(validators))
+	// clear the elements of the previous set
+	prev := c.pending.GetState(contract, common.BigToHash(new(big.Int).SetUint64(slot))).Big().Int64()
+	for _, key := range validatorStorageKeys(slot, int(prev))[1:] {
+		c.pending.SetState(contract, key, common.Hash{})
+	}
+	c.pending.SetState(contract, keys[0], common.BigToHash(big.NewInt(int64(len(validators)))))
+	for i, val := range validators {
+		c.pending.SetState(contract, keys[i+1], common.BytesToHash(val.Bytes()))
+	}
+}
+
+// contractValidators returns the validator set in the storage of the validator contract in the state
+func (c *testChain) contractValidators(state *testState) []common.Address {
+	contract := common.HexToAddress(c.config.ValidatorContractAddress)
+	slot := c.config.ValidatorContractSlot
+	length := state.GetState(contract, common.BigToHash(new(big.Int).SetUint64(slot))).Big().Int64()
+	var validators []common.Address
+	for _, key := range validatorStorageKeys(slot, int(length))[1:] {
+		validators = append(validators, common.BytesToAddress(state.GetState(contract, key).Bytes()))
+	}
+	return validators
+}
+
+// Mine produces the next block and returns its header
+func (c *testChain) Mine(opts ...testBlockOption) *gethtypes.Header {
+	var options testBlockOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	number := uint64(len(c.headers))
+
+	// the sealers of a block are in its extra data, or in the validator contract at the parent block
+	var sealers, extraValidators []common.Address
+	if c.config.UsesValidatorContract() {
+		if number > 0 {
+			sealers = c.contractValidators(c.states[number-1])
+		} else {
+			sealers = c.validators
+		}
+		extraValidators = []common.Address{}
+		if options.validators != nil {
+			c.validators = options.validators
+			c.setContractValidators(options.validators)
+		}
+	} else {
+		if options.validators != nil {
+			c.validators = options.validators
+		}
+		sealers = c.validators
+		extraValidators = c.validators
+	}
+
+	state := c.pending.copy()
+	header := &gethtypes.Header{
+		UncleHash:   gethtypes.EmptyUncleHash,
+		Root:        state.Root(),
+		TxHash:      gethtypes.EmptyTxsHash,
+		ReceiptHash: gethtypes.EmptyReceiptsHash,
+		Difficulty:  big.NewInt(1),
+		Number:      new(big.Int).SetUint64(number),
+		GasLimit:    30000000,
+		Time:        testGenesisTime + number*testBlockPeriod,
+		MixDigest:   testMixDigest,
+	}
+	if options.proposer != nil {
+		header.Coinbase = *options.proposer
+	} else if len(sealers) > 0 {
+		header.Coinbase = sealers[(number+uint64(options.round))%uint64(len(sealers))]
+	}
+	if number > 0 {
+		header.ParentHash = c.besuHash(c.headers[number-1])
+	}
+
+	extra := c.newExtraData(number, extraValidators, options.round)
+	header.Extra = c.encodeExtraData(number, extra, false)
+	hash := crypto.Keccak256(mustEncodeHeader(header))
+	signers := options.signers
+	if signers == nil {
+		signers = sealers
+	}
+	for _, signer := range signers {
+		seal, err := crypto.Sign(hash, c.keys[signer])
+		if err != nil {
+			panic(err)
+		}
+		extra.Seals = append(extra.Seals, seal)
+	}
+	if options.mutateSeals != nil {
+		extra.Seals = options.mutateSeals(extra.Seals)
+	}
+	header.Extra = c.encodeExtraData(number, extra, true)
+
+	c.headers = append(c.headers, header)
+	c.states = append(c.states, state)
+	return header
+}
+
+// MineN produces `n` blocks with the same options
+func (c *testChain) MineN(n int, opts ...testBlockOption) {
+	for i := 0; i < n; i++ {
+		c.Mine(opts...)
+	}
+}
+
+// newExtraData returns the extra data of the block without seals.
+// The round is an RLP integer in QBFT, and a 4-byte big endian integer in IBFT 2.0.
+func (c *testChain) newExtraData(number uint64, validators []common.Address, round uint32) *ExtraData {
+	var roundBytes []byte
+	if c.config.IsIBFT2At(number) {
+		roundBytes = binary.BigEndian.AppendUint32(nil, round)
+	} else if round > 0 {
+		roundBytes = new(big.Int).SetUint64(uint64(round)).Bytes()
+	}
+	return &ExtraData{
+		Vanity:     make([]byte, 32),
+		Validators: validators,
+		Vote:       []interface{}{},
+		Round:      roundBytes,
+	}
+}
+
+// encodeExtraData encodes the extra data as Besu does, where the extra data of a header to be sealed
+// has an empty list of seals in QBFT, and no seals in IBFT 2.0
+func (c *testChain) encodeExtraData(number uint64, extra *ExtraData, withSeals bool) []byte {
+	items := []interface{}{extra.Vanity, extra.Validators, extra.Vote, extra.Round}
+	if withSeals {
+		items = append(items, extra.Seals)
+	} else if !c.config.IsIBFT2At(number) {
+		items = append(items, [][]byte{})
+	}
+	bz, err := rlp.EncodeToBytes(items)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// besuHash returns the block hash of Besu, which is the hash of the header without seals
+func (c *testChain) besuHash(header *gethtypes.Header) common.Hash {
+	extra, err := parseExtraData(header.Extra)
+	if err != nil {
+		panic(err)
+	}
+	h := *header
+	h.Extra = c.encodeExtraData(header.Number.Uint64(), extra, false)
+	return crypto.Keccak256Hash(mustEncodeHeader(&h))
+}
+
+func mustEncodeHeader(header *gethtypes.Header) []byte {
+	bz, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// Head returns the latest header
+func (c *testChain) Head() *gethtypes.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.headers[len(c.headers)-1]
+}
+
+// Header returns the header at the number, or nil if it does not exist
+func (c *testChain) Header(number uint64) *gethtypes.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number]
+}
+
+// blockIndex returns the index of the block, where the tags refer to the latest block as QBFT has immediate finality
+func (c *testChain) blockIndex(number rpc.BlockNumber) (uint64, bool) {
+	head := uint64(len(c.headers) - 1)
+	if number < 0 {
+		return head, true
+	}
+	return uint64(number), uint64(number) <= head
+}
+
+// testEthAPI serves the JSON-RPC methods of the eth namespace used by the prover from testChain
+type testEthAPI struct {
+	chain *testChain
+}
+
+func (api *testEthAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.chain.Head().Number.Uint64())
+}
+
+func (api *testEthAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(testEthChainID))
+}
+
+func (api *testEthAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (*gethtypes.Header, error) {
+	api.chain.mu.Lock()
+	defer api.chain.mu.Unlock()
+	if i, ok := api.chain.blockIndex(number); ok {
+		return api.chain.headers[i], nil
+	}
+	return nil, nil
+}
+
+func (api *testEthAPI) GetProof(ctx context.Context, address common.Address, storageKeys []common.Hash, number rpc.BlockNumber) (*proofResult, error) {
+	api.chain.mu.Lock()
+	defer api.chain.mu.Unlock()
+	i, ok := api.chain.blockIndex(number)
+	if !ok {
+		return nil, fmt.Errorf("block not found: %v", number)
+	}
+	return api.chain.states[i].Proof(address, storageKeys), nil
+}
+
+type testCallArgs struct {
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+// Call only supports getValidators of the validator contract, which returns the validator set in its storage
+func (api *testEthAPI) Call(ctx context.Context, args testCallArgs, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	api.chain.mu.Lock()
+	defer api.chain.mu.Unlock()
+	i, ok := api.chain.blockIndex(number)
+	if !ok {
+		return nil, fmt.Errorf("block not found: %v", number)
+	}
+	method := validatorContractABI.Methods["getValidators"]
+	if !api.chain.config.UsesValidatorContract() || args.To == nil || *args.To != common.HexToAddress(api.chain.config.ValidatorContractAddress) || !bytes.HasPrefix(args.Input, method.ID) {
+		return nil, fmt.Errorf("unsupported call: to=%v input=%x", args.To, args.Input)
+	}
+	return method.Outputs.Pack(api.chain.contractValidators(api.chain.states[i]))
+}
+
+// newTestRPCServer serves the chain over HTTP and returns the URL
+func newTestRPCServer(t testing.TB, chain *testChain) string {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", &testEthAPI{chain: chain}); err != nil {
+		t.Fatal(err)
+	}
+	httpSrv := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		httpSrv.Close()
+		srv.Stop()
+	})
+	return httpSrv.URL
+}
+
+// newTestProverConfig returns the config of the prover for testChain
+func newTestProverConfig(consensusType string) ProverConfig {
+	return ProverConfig{
+		ConsensusType:  consensusType,
+		TrustingPeriod: "336h",
+		MaxClockDrift:  "10s",
+	}
+}
+
+// newTestProver returns the prover of the chain that is connected to the chain through a JSON-RPC server
+func newTestProver(t testing.TB, chain *testChain) *Prover {
+	return NewProver(newTestEthChain(t, newTestRPCServer(t, chain), testIBCAddress), chain.config)
+}
+
+// newTestEthChain returns the chain module of the relayer connected to `rpcAddr`
+func newTestEthChain(t testing.TB, rpcAddr string, ibcAddress common.Address) *ethereum.Chain {
+	signer, err := codectypes.NewAnyWithValue(&hd.SignerConfig{Mnemonic: testMnemonic, Path: testHDWalletPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethChain, err := ethereum.NewChain(ethereum.ChainConfig{
+		ChainId:              "ibc0",
+		EthChainId:           testEthChainID,
+		RpcAddr:              rpcAddr,
+		IbcAddress:           ibcAddress.Hex(),
+		Signer:               signer,
+		MaxRetryForInclusion: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ethChain
+}
@@ -0,0 +1,170 @@
+package module
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+)
+
+// testAccount is an account in testState
+type testAccount struct {
+	nonce   uint64
+	balance *big.Int
+	storage map[common.Hash]common.Hash
+}
+
+// testState is the world state of testChain, whose tries are rebuilt with the trie package of go-ethereum on each query,
+// which is only fast enough for the small states of the tests
+type testState struct {
+	accounts map[common.Address]*testAccount
+}
+
+func newTestState() *testState {
+	return &testState{accounts: make(map[common.Address]*testAccount)}
+}
+
+func (s *testState) copy() *testState {
+	cpy := newTestState()
+	for addr, acc := range s.accounts {
+		storage := make(map[common.Hash]common.Hash, len(acc.storage))
+		for k, v := range acc.storage {
+			storage[k] = v
+		}
+		cpy.accounts[addr] = &testAccount{nonce: acc.nonce, balance: new(big.Int).Set(acc.balance), storage: storage}
+	}
+	return cpy
+}
+
+func (s *testState) account(addr common.Address) *testAccount {
+	acc, ok := s.accounts[addr]
+	if !ok {
+		acc = &testAccount{balance: new(big.Int), storage: make(map[common.Hash]common.Hash)}
+		s.accounts[addr] = acc
+	}
+	return acc
+}
+
+// SetState sets the storage value of the account, or deletes it if the value is empty
+func (s *testState) SetState(addr common.Address, key, value common.Hash) {
+	acc := s.account(addr)
+	if value == (common.Hash{}) {
+		delete(acc.storage, key)
+	} else {
+		acc.storage[key] = value
+	}
+}
+
+func (s *testState) GetState(addr common.Address, key common.Hash) common.Hash {
+	if acc, ok := s.accounts[addr]; ok {
+		return acc.storage[key]
+	}
+	return common.Hash{}
+}
+
+func (s *testState) storageTrie(addr common.Address) *trie.Trie {
+	t := newGethTrie()
+	if acc, ok := s.accounts[addr]; ok {
+		for k, v := range acc.storage {
+			// the storage values are stored as RLP strings without leading zeros
+			value, err := rlp.EncodeToBytes(common.TrimLeftZeroes(v.Bytes()))
+			if err != nil {
+				panic(err)
+			}
+			t.MustUpdate(crypto.Keccak256(k.Bytes()), value)
+		}
+	}
+	return t
+}
+
+func (s *testState) stateAccount(addr common.Address) *gethtypes.StateAccount {
+	acc := s.accounts[addr]
+	return &gethtypes.StateAccount{
+		Nonce:    acc.nonce,
+		Balance:  uint256.MustFromBig(acc.balance),
+		Root:     s.storageTrie(addr).Hash(),
+		CodeHash: gethtypes.EmptyCodeHash.Bytes(),
+	}
+}
+
+func (s *testState) accountTrie() *trie.Trie {
+	t := newGethTrie()
+	for addr := range s.accounts {
+		value, err := rlp.EncodeToBytes(s.stateAccount(addr))
+		if err != nil {
+			panic(err)
+		}
+		t.MustUpdate(crypto.Keccak256(addr.Bytes()), value)
+	}
+	return t
+}
+
+// Root returns the state root
+func (s *testState) Root() common.Hash {
+	return s.accountTrie().Hash()
+}
+
+// Proof returns the result of eth_getProof for the account and the storage keys
+func (s *testState) Proof(addr common.Address, storageKeys []common.Hash) *proofResult {
+	res := &proofResult{
+		Balance:     new(hexutil.Big),
+		CodeHash:    gethtypes.EmptyCodeHash,
+		StorageHash: gethtypes.EmptyRootHash,
+	}
+	if err := s.accountTrie().Prove(crypto.Keccak256(addr.Bytes()), (*proofList)(&res.AccountProof)); err != nil {
+		panic(err)
+	}
+	if _, ok := s.accounts[addr]; ok {
+		account := s.stateAccount(addr)
+		res.Balance = (*hexutil.Big)(account.Balance.ToBig())
+		res.Nonce = hexutil.Uint64(account.Nonce)
+		res.StorageHash = account.Root
+	}
+	storageTrie := s.storageTrie(addr)
+	for _, key := range storageKeys {
+		var storage storageResult
+		if err := storageTrie.Prove(crypto.Keccak256(key.Bytes()), (*proofList)(&storage.Proof)); err != nil {
+			panic(err)
+		}
+		res.StorageProof = append(res.StorageProof, storage)
+	}
+	return res
+}
+
+func TestTestState(t *testing.T) {
+	state := newTestState()
+	for i := 0; i < 100; i++ {
+		state.SetState(testIBCAddress, common.BigToHash(big.NewInt(int64(i))), common.BigToHash(big.NewInt(int64(i+1))))
+	}
+	keys := []common.Hash{common.BigToHash(big.NewInt(10)), common.BigToHash(big.NewInt(100))}
+	res := state.Proof(testIBCAddress, keys)
+	accountProof, err := encodeProofNodes(res.AccountProof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageRoot, err := verifyAccountProof(state.Root(), testIBCAddress, accountProof)
+	if err != nil {
+		t.Fatal(err)
+	} else if storageRoot != res.StorageHash {
+		t.Fatalf("unexpected storage root: expected=%v actual=%v", res.StorageHash, storageRoot)
+	}
+	// the value at 100 does not exist
+	for i, expected := range []common.Hash{common.BigToHash(big.NewInt(11)), {}} {
+		storageProof, err := encodeProofNodes(res.StorageProof[i].Proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := verifyStorageProof(storageRoot, keys[i], storageProof)
+		if err != nil {
+			t.Fatal(err)
+		} else if value != expected {
+			t.Fatalf("unexpected value of %v: expected=%v actual=%v", keys[i], expected, value)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package module
+
+import (
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VerifyClientMessage checks if the clientMessage is of type Header or Misbehaviour and verifies it against the trusted consensus state
+func (cs *ClientState) VerifyClientMessage(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) error {
+	switch msg := clientMsg.(type) {
+	case *Header:
+		trustedConsState, found := GetConsensusState(clientStore, cdc, msg.TrustedHeight)
+		if !found {
+			return errorsmod.Wrapf(clienttypes.ErrConsensusStateNotFound, "could not get trusted consensus state from clientStore for Header at TrustedHeight: %s", msg.TrustedHeight)
+		}
+		_, err := cs.verifyHeader(trustedConsState, msg, ctx.BlockTime())
+		return err
+	case *Misbehaviour:
+		return cs.verifyMisbehaviour(ctx, cdc, clientStore, msg)
+	default:
+		return clienttypes.ErrInvalidClientType
+	}
+}
+
+// verifyHeader verifies the header against the trusted consensus state in the same way as QBFTClient.sol,
+// and returns the consensus state derived from the header.
+func (cs *ClientState) verifyHeader(trustedConsState *ConsensusState, header *Header, now time.Time) (*ConsensusState, error) {
+	if err := header.ValidateBasic(); err != nil {
+		return nil, errorsmod.Wrap(clienttypes.ErrInvalidHeader, err.Error())
+	}
+	ethHeader, err := header.EthHeader()
+	if err != nil {
+		return nil, errorsmod.Wrap(clienttypes.ErrInvalidHeader, err.Error())
+	}
+	if height := ethHeader.Number.Uint64(); height <= header.TrustedHeight.RevisionHeight {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "header height must be greater than trusted height: %v <= %v", height, header.TrustedHeight.RevisionHeight)
+	}
+
+	if err := cs.checkTimestamps(trustedConsState.Timestamp, ethHeader.Time, now); err != nil {
+		return nil, err
+	}
+
+	consState, err := cs.consensusStateFromEthHeader(header, ethHeader)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		trustedValidators   = bytesToAddresses(trustedConsState.Validators)
+		untrustedValidators = bytesToAddresses(consState.Validators)
+		headerHash          = crypto.Keccak256(header.BesuHeaderRlp)
+	)
+	if !verifyCommitSealsTrusting(trustedValidators, header.Seals, headerHash) {
+		return nil, errorsmod.Wrap(clienttypes.ErrInvalidHeader, "insufficient seals by the trusted validators")
+	}
+	if err := verifyCommitSeals(untrustedValidators, header.Seals, headerHash); err != nil {
+		return nil, err
+	}
+	return consState, nil
+}
+
+// checkTimestamps checks that the trusted consensus state is within the trusting period,
+// and that the header timestamp is after the trusted one and is not too far in the future.
+// All timestamps are in seconds.
+func (cs *ClientState) checkTimestamps(trustedTimestamp, headerTimestamp uint64, now time.Time) error {
+	if headerTimestamp <= trustedTimestamp {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "header timestamp must be greater than trusted timestamp: %v <= %v", headerTimestamp, trustedTimestamp)
+	}
+	// isExpired is always false if the trusting period is not set, but the clock drift is still checked
+	if cs.isExpired(trustedTimestamp, now) {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "trusted consensus state is expired: timestamp=%v trusting_period=%v now=%v", trustedTimestamp, cs.TrustingPeriod, now.Unix())
+	}
+	if maxTimestamp := now.Unix() + int64(cs.MaxClockDrift); int64(headerTimestamp) > maxTimestamp {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "header timestamp is too far in the future: %v > %v", headerTimestamp, maxTimestamp)
+	}
+	return nil
+}
+
+// isExpired returns whether the consensus state at `timestamp` (in seconds) has passed the trusting period
+func (cs *ClientState) isExpired(timestamp uint64, now time.Time) bool {
+	if cs.TrustingPeriod == 0 {
+		return false
+	}
+	return timestamp+cs.TrustingPeriod <= uint64(now.Unix())
+}
+
+// consensusStateFromHeader returns the consensus state derived from the header.
+// The storage root of the IBC contract is obtained by verifying the account proof against the state root of the header.
+func (cs *ClientState) consensusStateFromHeader(header *Header) (*ConsensusState, error) {
+	ethHeader, err := header.EthHeader()
+	if err != nil {
+		return nil, errorsmod.Wrap(clienttypes.ErrInvalidHeader, err.Error())
+	}
+	return cs.consensusStateFromEthHeader(header, ethHeader)
+}
+
+// consensusStateFromEthHeader is consensusStateFromHeader with the eth header already decoded from the header
+func (cs *ClientState) consensusStateFromEthHeader(header *Header, ethHeader *types.Header) (*ConsensusState, error) {
+	validators, err := cs.verifyValidators(header, ethHeader)
+	if err != nil {
+		return nil, err
+	}
+	storageRoot, err := verifyAccountProof(ethHeader.Root, common.BytesToAddress(cs.IbcStoreAddress), header.AccountStateProof)
+	if err != nil {
+		return nil, errorsmod.Wrap(clienttypes.ErrInvalidHeader, err.Error())
+	}
+	var vals [][]byte
+	for _, val := range validators {
+		vals = append(vals, val.Bytes())
+	}
+	return &ConsensusState{
+		Timestamp:  ethHeader.Time,
+		Root:       storageRoot.Bytes(),
+		Validators: vals,
+	}, nil
+}
+
+// verifyValidators returns the validators that sealed the header.
+// If the client uses the validator contract, they are verified with the validator contract proof in the header.
+func (cs *ClientState) verifyValidators(header *Header, ethHeader *types.Header) ([]common.Address, error) {
+	if len(cs.ValidatorContractAddress) == 0 {
+		if header.ValidatorContractProof != nil {
+			return nil, errorsmod.Wrap(clienttypes.ErrInvalidHeader, "validator contract proof is set, but the client does not use the validator contract")
+		}
+		validators, err := parseValidators(ethHeader.Extra)
+		if err != nil {
+			return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to parse extra data: %v", err)
+		}
+		return validators, nil
+	}
+	if header.ValidatorContractProof == nil {
+		return nil, errorsmod.Wrap(clienttypes.ErrInvalidHeader, "validator contract proof is missing")
+	}
+	validators, err := verifyValidatorContractProof(ethHeader, common.BytesToAddress(cs.ValidatorContractAddress), cs.ValidatorContractSlot, header.ValidatorContractProof)
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to verify validator contract proof: %v", err)
+	}
+	return validators, nil
+}
+
+// verifyCommitSealsTrusting returns true if more than 1/3 of `trustedValidators` sealed the header.
+// The seals may be in any order.
+func verifyCommitSealsTrusting(trustedValidators []common.Address, seals [][]byte, headerHash []byte) bool {
+	return countTrustedSeals(trustedValidators, seals, headerHash)*3 > len(trustedValidators)
+}
+
+// countTrustedSeals returns the number of `trustedValidators` that sealed the header
+func countTrustedSeals(trustedValidators []common.Address, seals [][]byte, headerHash []byte) int {
+	trusted := make(map[common.Address]bool)
+	for _, val := range trustedValidators {
+		trusted[val] = true
+	}
+	count := 0
+	for _, seal := range seals {
+		if len(seal) == 0 {
+			continue
+		}
+		addr, err := ecrecover(headerHash, seal)
+		if err != nil || !trusted[addr] {
+			continue
+		}
+		// count each trusted validator only once
+		trusted[addr] = false
+		count++
+	}
+	return count
+}
+
+// verifyCommitSeals checks that more than 2/3 of `validators` sealed the header.
+// `seals` must be ordered by `validators`, and nil (or empty) seals are skipped.
+func verifyCommitSeals(validators []common.Address, seals [][]byte, headerHash []byte) error {
+	if len(validators) != len(seals) {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "the number of seals is not equal to the number of validators: %v != %v", len(seals), len(validators))
+	}
+	count := 0
+	for i, seal := range seals {
+		if len(seal) == 0 {
+			continue
+		}
+		addr, err := ecrecover(headerHash, seal)
+		if err != nil {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to recover seal[%v]: %v", i, err)
+		}
+		if addr != validators[i] {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "seal[%v] is not signed by the validator: expected=%v actual=%v", i, validators[i], addr)
+		}
+		count++
+	}
+	if threshold := len(validators) * 2 / 3; count <= threshold {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "insufficient voting: %v > %v", count, threshold)
+	}
+	return nil
+}
+
+// UpdateState stores the consensus state derived from the header and updates the latest height if necessary.
+// It assumes that the header has already been verified by VerifyClientMessage.
+// Since the interface has no error return, no state is updated and no height is returned for an invalid client message.
+func (cs *ClientState) UpdateState(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) []exported.Height {
+	heights, err := cs.updateState(ctx, cdc, clientStore, clientMsg)
+	if err != nil {
+		return nil
+	}
+	return heights
+}
+
+// updateState is UpdateState that returns an error for an invalid client message instead of panicking
+func (cs *ClientState) updateState(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) ([]exported.Height, error) {
+	header, ok := clientMsg.(*Header)
+	if !ok {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidClientType, "expected type %T, got %T", &Header{}, clientMsg)
+	}
+	height := header.GetHeight()
+
+	// check for duplicate update
+	if _, found := GetConsensusState(clientStore, cdc, height); found {
+		// perform no-op
+		return []exported.Height{height}, nil
+	}
+
+	consState, err := cs.consensusStateFromHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	if height.GT(cs.LatestHeight) {
+		cs.LatestHeight = height.(clienttypes.Height)
+	}
+
+	setClientState(clientStore, cdc, cs)
+	setConsensusState(clientStore, cdc, consState, height)
+	setConsensusMetadata(ctx, clientStore, height)
+
+	return []exported.Height{height}, nil
+}
+
+func bytesToAddresses(bzs [][]byte) []common.Address {
+	var addrs []common.Address
+	for _, bz := range bzs {
+		addrs = append(addrs, common.BytesToAddress(bz))
+	}
+	return addrs
+}
@@ -0,0 +1,222 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/store/dbadapter"
+	storetypes "cosmossdk.io/store/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testLightClient is the light client of testChain, whose states are in an in-memory client store
+type testLightClient struct {
+	cdc         codec.BinaryCodec
+	store       storetypes.KVStore
+	clientState *ClientState
+}
+
+// newTestLightClient creates the light client with the initial states at `height` built by the prover
+func newTestLightClient(t *testing.T, pr *Prover, height uint64) *testLightClient {
+	cs, cons, err := pr.CreateInitialLightClientState(pr.newHeight(int64(height)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := codectypes.NewInterfaceRegistry()
+	Module{}.RegisterInterfaces(registry)
+	lc := &testLightClient{
+		cdc:         codec.NewProtoCodec(registry),
+		store:       dbadapter.Store{DB: dbm.NewMemDB()},
+		clientState: cs.(*ClientState),
+	}
+	now := time.Unix(int64(cons.(*ConsensusState).Timestamp), 0)
+	if err := lc.clientState.Initialize(lc.ctx(now), lc.cdc, lc.store, cons); err != nil {
+		t.Fatal(err)
+	}
+	return lc
+}
+
+func (lc *testLightClient) ctx(now time.Time) sdk.Context {
+	return sdk.Context{}.WithBlockTime(now)
+}
+
+// update verifies the client message and updates the client with it in the same way as the IBC module
+func (lc *testLightClient) update(clientMsg exported.ClientMessage, now time.Time) error {
+	ctx := lc.ctx(now)
+	if err := lc.clientState.VerifyClientMessage(ctx, lc.cdc, lc.store, clientMsg); err != nil {
+		return err
+	}
+	if lc.clientState.CheckForMisbehaviour(ctx, lc.cdc, lc.store, clientMsg) {
+		lc.clientState.UpdateStateOnMisbehaviour(ctx, lc.cdc, lc.store, clientMsg)
+		return nil
+	}
+	lc.clientState.UpdateState(ctx, lc.cdc, lc.store, clientMsg)
+	return nil
+}
+
+// newTestHeader returns the header at the number built by the prover, whose seals can be modified by the caller
+func newTestHeader(t *testing.T, pr *Prover, number uint64, trustedHeight clienttypes.Height) *Header {
+	decoded, err := pr.getHeader(context.Background(), new(big.Int).SetUint64(number))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := decoded.header
+	header.TrustedHeight = trustedHeight
+	header.Seals = append([][]byte{}, header.Seals...)
+	return header
+}
+
+func TestVerifyHeader(t *testing.T) {
+	outsider, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name string
+		// mine produces the target block on the chain, whose trusted validators are `trusted`
+		mine   func(chain *testChain, trusted []common.Address)
+		mutate func(header *Header)
+		// elapsed is the time from the target block to the verification
+		elapsed time.Duration
+		// err is the substring of the error, or empty if the header is valid
+		err string
+	}{
+		{name: "valid"},
+		{name: "own seals over 2/3", mutate: func(header *Header) {
+			header.Seals[3] = nil
+		}},
+		{name: "own seals not over 2/3", mutate: func(header *Header) {
+			header.Seals[2], header.Seals[3] = nil, nil
+		}, err: "insufficient voting"},
+		{name: "trusted seals over 1/3", mine: func(chain *testChain, trusted []common.Address) {
+			next := append(chain.NewKeys(2), trusted[:2]...)
+			sortAddresses(next)
+			chain.Mine(withValidators(next...))
+		}},
+		{name: "trusted seals not over 1/3", mine: func(chain *testChain, trusted []common.Address) {
+			next := append(chain.NewKeys(3), trusted[0])
+			sortAddresses(next)
+			chain.Mine(withValidators(next...))
+		}, err: "insufficient seals by the trusted validators"},
+		{name: "all validators replaced", mine: func(chain *testChain, trusted []common.Address) {
+			chain.Mine(withValidators(chain.NewKeys(4)...))
+		}, err: "insufficient seals by the trusted validators"},
+		{name: "forged seal", mutate: func(header *Header) {
+			seal, err := crypto.Sign(crypto.Keccak256(header.BesuHeaderRlp), outsider)
+			if err != nil {
+				t.Fatal(err)
+			}
+			header.Seals[0] = seal
+		}, err: "seal[0] is not signed by the validator"},
+		{name: "duplicate seal", mutate: func(header *Header) {
+			header.Seals[1] = header.Seals[0]
+		}, err: "seal[1] is not signed by the validator"},
+		{name: "expired", elapsed: 336 * time.Hour, err: "trusted consensus state is expired"},
+		{name: "too far in the future", elapsed: -time.Minute, err: "header timestamp is too far in the future"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+			pr := newTestProver(t, chain)
+			chain.MineN(2)
+			lc := newTestLightClient(t, pr, 2)
+			trustedHeight := lc.clientState.LatestHeight
+			if c.mine != nil {
+				c.mine(chain, chain.validators)
+			} else {
+				chain.Mine()
+			}
+
+			header := newTestHeader(t, pr, chain.Head().Number.Uint64(), trustedHeight)
+			if c.mutate != nil {
+				c.mutate(header)
+			}
+			now := time.Unix(int64(chain.Head().Time), 0).Add(c.elapsed)
+			err := lc.update(header, now)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("unexpected error: expected=%q actual=%v", c.err, err)
+				}
+				if _, found := GetConsensusState(lc.store, lc.cdc, header.GetHeight()); found {
+					t.Fatal("consensus state of invalid header is stored")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !lc.clientState.LatestHeight.EQ(header.GetHeight()) {
+				t.Fatalf("unexpected latest height: expected=%v actual=%v", header.GetHeight(), lc.clientState.LatestHeight)
+			}
+			consState, found := GetConsensusState(lc.store, lc.cdc, header.GetHeight())
+			if !found {
+				t.Fatal("consensus state is not stored")
+			}
+			if validators := bytesToAddresses(consState.Validators); len(validators) != len(chain.validators) {
+				t.Fatalf("unexpected validators: expected=%v actual=%v", chain.validators, validators)
+			}
+			for i, val := range bytesToAddresses(consState.Validators) {
+				if val != chain.validators[i] {
+					t.Fatalf("unexpected validator[%v]: expected=%v actual=%v", i, chain.validators[i], val)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateStateInvalidMessage(t *testing.T) {
+	chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+	pr := newTestProver(t, chain)
+	chain.MineN(2)
+	lc := newTestLightClient(t, pr, 2)
+	ctx := lc.ctx(time.Unix(int64(chain.Head().Time), 0))
+
+	if _, err := lc.clientState.updateState(ctx, lc.cdc, lc.store, &Misbehaviour{}); !errors.Is(err, clienttypes.ErrInvalidClientType) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// UpdateState does not panic, and updates nothing
+	if heights := lc.clientState.UpdateState(ctx, lc.cdc, lc.store, &Misbehaviour{}); len(heights) != 0 {
+		t.Fatalf("unexpected heights: %v", heights)
+	}
+	if height := lc.clientState.LatestHeight.RevisionHeight; height != 2 {
+		t.Fatalf("unexpected latest height: %v", height)
+	}
+}
+
+func TestCheckTimestamps(t *testing.T) {
+	now := time.Unix(10000, 0)
+	for _, c := range []struct {
+		name             string
+		trustingPeriod   uint64
+		trustedTimestamp uint64
+		headerTimestamp  uint64
+		valid            bool
+	}{
+		{name: "valid", trustingPeriod: 1000, trustedTimestamp: 9500, headerTimestamp: 9990, valid: true},
+		{name: "not after trusted", trustingPeriod: 1000, trustedTimestamp: 9500, headerTimestamp: 9500},
+		{name: "expired", trustingPeriod: 1000, trustedTimestamp: 9000, headerTimestamp: 9990},
+		{name: "within drift", trustingPeriod: 1000, trustedTimestamp: 9500, headerTimestamp: 10010, valid: true},
+		{name: "too far in future", trustingPeriod: 1000, trustedTimestamp: 9500, headerTimestamp: 10011},
+		{name: "no trusting period", trustedTimestamp: 1, headerTimestamp: 10010, valid: true},
+		{name: "too far in future without trusting period", trustedTimestamp: 1, headerTimestamp: 10011},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			cs := &ClientState{TrustingPeriod: c.trustingPeriod, MaxClockDrift: 10}
+			err := cs.checkTimestamps(c.trustedTimestamp, c.headerTimestamp, now)
+			if c.valid && err != nil {
+				t.Fatal(err)
+			} else if !c.valid && err == nil {
+				t.Fatal("invalid timestamps are accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package module
+
+import (
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	KeyUpgradedIBCState  = "upgradedIBCState"
+	KeyUpgradedClient    = "upgradedClient"
+	KeyUpgradedConsState = "upgradedConsState"
+)
+
+// UpgradedClientStatePath returns the commitment path of the upgraded client state for the revision
+func UpgradedClientStatePath(revisionNumber uint64) string {
+	return fmt.Sprintf("%s/%d/%s", KeyUpgradedIBCState, revisionNumber, KeyUpgradedClient)
+}
+
+// UpgradedConsensusStatePath returns the commitment path of the upgraded consensus state for the revision
+func UpgradedConsensusStatePath(revisionNumber uint64) string {
+	return fmt.Sprintf("%s/%d/%s", KeyUpgradedIBCState, revisionNumber, KeyUpgradedConsState)
+}
+
+// VerifyUpgradeAndUpdateState checks if the upgraded client and consensus states have been committed in the IBC contract
+// at the latest height of the client, and then replaces the client and consensus states with them.
+// The upgraded client state must be committed with its custom fields zeroed out, which are inherited from the current client state.
+// This allows the client to follow the chain across a hard reset, where the chain is restarted with a new revision number.
+func (cs *ClientState) VerifyUpgradeAndUpdateState(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, upgradedClient exported.ClientState, upgradedConsState exported.ConsensusState, proofUpgradeClient, proofUpgradeConsState []byte) error {
+	newClientState, ok := upgradedClient.(*ClientState)
+	if !ok {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidClientType, "upgraded client must be qbft client. expected: %T got: %T", &ClientState{}, upgradedClient)
+	}
+	newConsState, ok := upgradedConsState.(*ConsensusState)
+	if !ok {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "upgraded consensus state must be qbft consensus state. expected %T, got: %T", &ConsensusState{}, upgradedConsState)
+	}
+	if err := newClientState.Validate(); err != nil {
+		return errorsmod.Wrap(clienttypes.ErrInvalidUpgradeClient, err.Error())
+	}
+	if err := newConsState.ValidateBasic(); err != nil {
+		return errorsmod.Wrap(clienttypes.ErrInvalidUpgradeClient, err.Error())
+	}
+	revisionNumber := newClientState.LatestHeight.RevisionNumber
+	if revisionNumber <= cs.LatestHeight.RevisionNumber {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidUpgradeClient, "upgraded client must have a greater revision number: %v <= %v", revisionNumber, cs.LatestHeight.RevisionNumber)
+	}
+
+	consState, found := GetConsensusState(clientStore, cdc, cs.LatestHeight)
+	if !found {
+		return errorsmod.Wrap(clienttypes.ErrConsensusStateNotFound, "could not retrieve consensus state for latest height")
+	}
+	root := common.BytesToHash(consState.Root)
+
+	// verify the commitments of the upgraded states
+	bz, err := cdc.MarshalInterface(newClientState.ZeroCustomFields())
+	if err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "could not marshal client state: %v", err)
+	}
+	if err := verifyUpgradeCommitment(root, cs.commitmentsSlot(), UpgradedClientStatePath(revisionNumber), bz, proofUpgradeClient); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidUpgradeClient, "client state proof failed: %v", err)
+	}
+	bz, err = cdc.MarshalInterface(newConsState)
+	if err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "could not marshal consensus state: %v", err)
+	}
+	if err := verifyUpgradeCommitment(root, cs.commitmentsSlot(), UpgradedConsensusStatePath(revisionNumber), bz, proofUpgradeConsState); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidUpgradeClient, "consensus state proof failed: %v", err)
+	}
+
+	newClientState = &ClientState{
+		ChainId:                  newClientState.ChainId,
+		IbcStoreAddress:          newClientState.IbcStoreAddress,
+		LatestHeight:             newClientState.LatestHeight,
+		TrustingPeriod:           cs.TrustingPeriod,
+		MaxClockDrift:            cs.MaxClockDrift,
+		ValidatorContractAddress: newClientState.ValidatorContractAddress,
+		ValidatorContractSlot:    newClientState.ValidatorContractSlot,
+		CommitmentsSlot:          newClientState.CommitmentsSlot,
+	}
+	setClientState(clientStore, cdc, newClientState)
+	setConsensusState(clientStore, cdc, newConsState, newClientState.LatestHeight)
+	setConsensusMetadata(ctx, clientStore, newClientState.LatestHeight)
+	return nil
+}
+
+// verifyUpgradeCommitment verifies that keccak256(value) is committed at `path` in the commitments mapping at `slot` of the IBC contract
+func verifyUpgradeCommitment(storageRoot common.Hash, slot common.Hash, path string, value []byte, proof []byte) error {
+	storageValue, err := verifyStorageProof(storageRoot, commitmentStorageKey([]byte(path), slot), proof)
+	if err != nil {
+		return err
+	}
+	if expected := crypto.Keccak256Hash(value); storageValue != expected {
+		return fmt.Errorf("commitment mismatch: path=%v expected=%v actual=%v", path, expected, storageValue)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package module
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVerifyUpgradeAndUpdateState(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		// tamper modifies the upgraded states after they are committed
+		tamper func(clientState *ClientState, consState *ConsensusState, clientProof, consProof *[]byte)
+		// err is the substring of the error, or empty if the upgrade succeeds
+		err string
+	}{
+		{name: "valid"},
+		{name: "custom fields are not committed", tamper: func(clientState *ClientState, consState *ConsensusState, clientProof, consProof *[]byte) {
+			clientState.TrustingPeriod = 1
+		}},
+		{name: "tampered client state", tamper: func(clientState *ClientState, consState *ConsensusState, clientProof, consProof *[]byte) {
+			clientState.IbcStoreAddress = common.HexToAddress("0x01").Bytes()
+		}, err: "client state proof failed"},
+		{name: "tampered consensus state", tamper: func(clientState *ClientState, consState *ConsensusState, clientProof, consProof *[]byte) {
+			consState.Timestamp++
+		}, err: "consensus state proof failed"},
+		{name: "swapped proofs", tamper: func(clientState *ClientState, consState *ConsensusState, clientProof, consProof *[]byte) {
+			*clientProof, *consProof = *consProof, *clientProof
+		}, err: "client state proof failed"},
+		{name: "same revision", tamper: func(clientState *ClientState, consState *ConsensusState, clientProof, consProof *[]byte) {
+			clientState.LatestHeight.RevisionNumber = 0
+		}, err: "upgraded client must have a greater revision number"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			chain := newTestChain(t, newTestProverConfig(QBFTConsensusType), 4)
+			pr := newTestProver(t, chain)
+			lc := newTestLightClient(t, pr, 0)
+
+			// the chain is restarted with revision 1 after the upgraded states are committed
+			upgradedClientState := *lc.clientState
+			upgradedClientState.LatestHeight = clienttypes.NewHeight(1, 1)
+			upgradedConsState := &ConsensusState{
+				Timestamp:  uint64(time.Now().Unix()),
+				Root:       crypto.Keccak256([]byte("root")),
+				Validators: [][]byte{common.HexToAddress("0x02").Bytes()},
+			}
+			clientBz, err := lc.cdc.MarshalInterface(upgradedClientState.ZeroCustomFields())
+			if err != nil {
+				t.Fatal(err)
+			}
+			consBz, err := lc.cdc.MarshalInterface(upgradedConsState)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clientPath, consPath := UpgradedClientStatePath(1), UpgradedConsensusStatePath(1)
+			chain.SetState(testIBCAddress, commitmentStorageKey([]byte(clientPath), IBCCommitmentsSlot), crypto.Keccak256Hash(clientBz))
+			chain.SetState(testIBCAddress, commitmentStorageKey([]byte(consPath), IBCCommitmentsSlot), crypto.Keccak256Hash(consBz))
+			chain.Mine()
+			if err := lc.update(newTestHeader(t, pr, 1, lc.clientState.LatestHeight), time.Unix(int64(chain.Head().Time), 0)); err != nil {
+				t.Fatal(err)
+			}
+
+			clientProof, err := pr.buildStateProof([]byte(clientPath), clientBz, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			consProof, err := pr.buildStateProof([]byte(consPath), consBz, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.tamper != nil {
+				c.tamper(&upgradedClientState, upgradedConsState, &clientProof, &consProof)
+			}
+
+			trustingPeriod := lc.clientState.TrustingPeriod
+			ctx := lc.ctx(time.Unix(int64(chain.Head().Time), 0))
+			err = lc.clientState.VerifyUpgradeAndUpdateState(ctx, lc.cdc, lc.store, &upgradedClientState, upgradedConsState, clientProof, consProof)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("unexpected error: expected=%q actual=%v", c.err, err)
+				}
+				if _, found := GetConsensusState(lc.store, lc.cdc, clienttypes.NewHeight(1, 1)); found {
+					t.Fatal("the upgraded consensus state is stored")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			consState, found := GetConsensusState(lc.store, lc.cdc, upgradedClientState.LatestHeight)
+			if !found {
+				t.Fatal("the upgraded consensus state is not stored")
+			} else if consState.Timestamp != upgradedConsState.Timestamp {
+				t.Fatalf("unexpected consensus state: expected=%v actual=%v", upgradedConsState, consState)
+			}
+			clientState, err := clienttypes.UnmarshalClientState(lc.cdc, lc.store.Get(host.ClientStateKey()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if upgraded := clientState.(*ClientState); !upgraded.LatestHeight.EQ(upgradedClientState.LatestHeight) {
+				t.Fatalf("unexpected latest height: expected=%v actual=%v", upgradedClientState.LatestHeight, upgraded.LatestHeight)
+			} else if upgraded.TrustingPeriod != trustingPeriod {
+				// the custom fields are inherited from the current client state
+				t.Fatalf("unexpected trusting period: expected=%v actual=%v", trustingPeriod, upgraded.TrustingPeriod)
+			}
+		})
+	}
+}
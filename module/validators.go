@@ -0,0 +1,193 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// validatorContractABI is the ABI of the validator contract interface that Besu requires
+var validatorContractABI = mustParseABI(`[{"inputs":[],"name":"getValidators","outputs":[{"internalType":"address[]","name":"","type":"address[]"}],"stateMutability":"view","type":"function"}]`)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// getValidators returns the validator set that seals the block.
+// If the validator contract is configured, the validator set is read from the contract at the parent block,
+// and the proof of the validator set against the state root of the parent block is returned together.
+func (pr *Prover) getValidators(ctx context.Context, header *gethtypes.Header, extra *ExtraData) ([]common.Address, *ValidatorContractProof, error) {
+	if !pr.config.UsesValidatorContract() {
+		return extra.Validators, nil, nil
+	}
+	if header.Number.Sign() == 0 {
+		return nil, nil, fmt.Errorf("the validator set of the genesis block cannot be proven by the validator contract")
+	}
+	parentNumber := new(big.Int).Sub(header.Number, big.NewInt(1))
+	parent, err := pr.client.HeaderByNumber(ctx, parentNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentExtra, err := pr.getExtraData(parent)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentBytes, err := pr.encodeHeaderWithoutSeals(*parent, *parentExtra)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contract := common.HexToAddress(pr.config.ValidatorContractAddress)
+	validators, err := pr.callGetValidators(ctx, contract, parentNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	var storageKeys [][]byte
+	for _, key := range validatorStorageKeys(pr.config.ValidatorContractSlot, len(validators)) {
+		keyHex, err := key.MarshalText()
+		if err != nil {
+			return nil, nil, err
+		}
+		storageKeys = append(storageKeys, keyHex)
+	}
+	proof, err := pr.client.GetProof(contract, storageKeys, parentNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	var vals [][]byte
+	for _, val := range validators {
+		vals = append(vals, val.Bytes())
+	}
+	return validators, &ValidatorContractProof{
+		Validators:      vals,
+		ParentHeaderRlp: parentBytes,
+		AccountProof:    proof.AccountProofRLP,
+		StorageProofs:   proof.StorageProofRLP,
+	}, nil
+}
+
+// selectValidators returns the validator sets that seal the blocks in the same way as getValidators, but without the proofs.
+// If the validator contract is configured, the contract is called at the parent blocks in a batch.
+func (pr *Prover) selectValidators(ctx context.Context, headers []*gethtypes.Header, extras []*ExtraData) ([][]common.Address, error) {
+	validators := make([][]common.Address, len(headers))
+	if !pr.config.UsesValidatorContract() {
+		for i, extra := range extras {
+			validators[i] = extra.Validators
+		}
+		return validators, nil
+	}
+	var parentNumbers []*big.Int
+	for _, header := range headers {
+		if header.Number.Sign() == 0 {
+			return nil, fmt.Errorf("the validator set of the genesis block cannot be read from the validator contract")
+		}
+		parentNumbers = append(parentNumbers, new(big.Int).Sub(header.Number, big.NewInt(1)))
+	}
+	if len(parentNumbers) == 0 {
+		return validators, nil
+	}
+	data, err := validatorContractABI.Pack("getValidators")
+	if err != nil {
+		return nil, err
+	}
+	contract := common.HexToAddress(pr.config.ValidatorContractAddress)
+	results, err := pr.client.CallContractByNumbers(ctx, ethereum.CallMsg{To: &contract, Data: data}, parentNumbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getValidators: contract=%v numbers=%v-%v: %v", contract, parentNumbers[0], parentNumbers[len(parentNumbers)-1], err)
+	}
+	for i, res := range results {
+		if validators[i], err = unpackValidators(res); err != nil {
+			return nil, err
+		}
+	}
+	return validators, nil
+}
+
+// callGetValidators calls getValidators of the validator contract at the block
+func (pr *Prover) callGetValidators(ctx context.Context, contract common.Address, number *big.Int) ([]common.Address, error) {
+	data, err := validatorContractABI.Pack("getValidators")
+	if err != nil {
+		return nil, err
+	}
+	res, err := pr.client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getValidators: contract=%v number=%v: %v", contract, number, err)
+	}
+	return unpackValidators(res)
+}
+
+// unpackValidators unpacks the result of getValidators
+func unpackValidators(res []byte) ([]common.Address, error) {
+	outputs, err := validatorContractABI.Unpack("getValidators", res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack the result of getValidators: %v", err)
+	}
+	validators, ok := outputs[0].([]common.Address)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result of getValidators: %T", outputs[0])
+	}
+	return validators, nil
+}
+
+// validatorStorageKeys returns the storage keys of the length of the validator array at `slot` followed by each of its elements
+func validatorStorageKeys(slot uint64, length int) []common.Hash {
+	slotHash := common.BigToHash(new(big.Int).SetUint64(slot))
+	keys := []common.Hash{slotHash}
+	base := new(big.Int).SetBytes(crypto.Keccak256(slotHash.Bytes()))
+	for i := 0; i < length; i++ {
+		keys = append(keys, common.BigToHash(new(big.Int).Add(base, big.NewInt(int64(i)))))
+	}
+	return keys
+}
+
+// verifyValidatorContractProof verifies the validator set in the proof against the state root of the parent block of the header
+func verifyValidatorContractProof(header *gethtypes.Header, contract common.Address, slot uint64, proof *ValidatorContractProof) ([]common.Address, error) {
+	if parentHash := crypto.Keccak256Hash(proof.ParentHeaderRlp); parentHash != header.ParentHash {
+		return nil, fmt.Errorf("parent header hash mismatch: expected=%v actual=%v", header.ParentHash, parentHash)
+	}
+	var parent gethtypes.Header
+	if err := rlp.DecodeBytes(proof.ParentHeaderRlp, &parent); err != nil {
+		return nil, fmt.Errorf("failed to decode parent header: %v", err)
+	}
+	storageRoot, err := verifyAccountProof(parent.Root, contract, proof.AccountProof)
+	if err != nil {
+		return nil, err
+	}
+	keys := validatorStorageKeys(slot, len(proof.Validators))
+	if len(proof.StorageProofs) != len(keys) {
+		return nil, fmt.Errorf("unexpected number of storage proofs: expected=%v actual=%v", len(keys), len(proof.StorageProofs))
+	}
+	// the first slot holds the length of the array
+	length, err := verifyStorageProof(storageRoot, keys[0], proof.StorageProofs[0])
+	if err != nil {
+		return nil, err
+	} else if length != common.BigToHash(big.NewInt(int64(len(proof.Validators)))) {
+		return nil, fmt.Errorf("validator array length mismatch: expected=%v actual=%v", len(proof.Validators), length.Big())
+	}
+	var validators []common.Address
+	for i, val := range proof.Validators {
+		if len(val) != common.AddressLength {
+			return nil, fmt.Errorf("validator[%v] must be %v bytes: length=%v", i, common.AddressLength, len(val))
+		}
+		value, err := verifyStorageProof(storageRoot, keys[i+1], proof.StorageProofs[i+1])
+		if err != nil {
+			return nil, err
+		} else if value != common.BytesToHash(val) {
+			return nil, fmt.Errorf("validator[%v] mismatch: expected=%x actual=%v", i, val, value)
+		}
+		validators = append(validators, common.BytesToAddress(val))
+	}
+	return validators, nil
+}
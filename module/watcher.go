@@ -0,0 +1,122 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+// MisbehaviourWatcher compares the headers at the same height obtained from multiple RPC endpoints,
+// and builds a misbehaviour if two of them are sealed but conflict with each other
+type MisbehaviourWatcher struct {
+	endpoints []string
+	provers   []*Prover
+}
+
+// NewMisbehaviourWatcher returns a watcher that queries the chain of the prover through `endpoints`
+func NewMisbehaviourWatcher(pr *Prover, endpoints []string) (*MisbehaviourWatcher, error) {
+	if len(endpoints) < 2 {
+		return nil, fmt.Errorf("at least 2 endpoints are required: %v", endpoints)
+	}
+	w := &MisbehaviourWatcher{endpoints: endpoints}
+	for _, endpoint := range endpoints {
+		cl, err := client.NewETHClient(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %v: %v", endpoint, err)
+		}
+		w.provers = append(w.provers, pr.withClient(rpcClient{cl}))
+	}
+	return w, nil
+}
+
+// CheckHeight returns the misbehaviour if conflicting headers at the height are obtained from the endpoints, or nil otherwise.
+// The trusted height of the headers in the misbehaviour is set to `trustedHeight`,
+// which must be the height of a consensus state of the client the misbehaviour is submitted to.
+func (w *MisbehaviourWatcher) CheckHeight(ctx context.Context, height uint64, trustedHeight clienttypes.Height) (*Misbehaviour, error) {
+	logger := w.getLogger()
+	var headers []*Header
+	for i, pr := range w.provers {
+		decoded, err := pr.getHeader(ctx, new(big.Int).SetUint64(height))
+		if err != nil {
+			// a header that is not sufficiently sealed is not evidence of misbehaviour
+			logger.Error("failed to get a sealed header", err, "endpoint", w.endpoints[i], "height", height)
+			continue
+		}
+		header := decoded.header
+		header.TrustedHeight = trustedHeight
+		for _, h := range headers {
+			if !bytes.Equal(h.BesuHeaderRlp, header.BesuHeaderRlp) {
+				logger.Warn("conflicting headers are found", "endpoint", w.endpoints[i], "height", height)
+				return &Misbehaviour{Header1: h, Header2: header}, nil
+			}
+		}
+		headers = append(headers, header)
+	}
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("failed to get a sealed header at %v from any endpoint", height)
+	}
+	return nil, nil
+}
+
+// Watch checks every block from `startHeight` as the endpoints produce it until the context is done,
+// and calls `handler` for each misbehaviour found.
+// `trustedHeight` is called for each misbehaviour to get the trusted height of its headers.
+func (w *MisbehaviourWatcher) Watch(ctx context.Context, startHeight uint64, interval time.Duration, trustedHeight func() (clienttypes.Height, error), handler func(*Misbehaviour) error) error {
+	logger := w.getLogger()
+	next := startHeight
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		latest, err := w.latestHeight(ctx)
+		if err != nil {
+			logger.Error("failed to get the latest height", err)
+		}
+		for ; err == nil && next <= latest; next++ {
+			trusted, err := trustedHeight()
+			if err != nil {
+				return err
+			}
+			misbehaviour, err := w.CheckHeight(ctx, next, trusted)
+			if err != nil {
+				logger.Error("failed to check the height", err, "height", next)
+				break
+			}
+			if misbehaviour == nil {
+				continue
+			}
+			if err := handler(misbehaviour); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// latestHeight returns the lowest latest height among the endpoints, so that every endpoint can serve the headers up to it
+func (w *MisbehaviourWatcher) latestHeight(ctx context.Context) (uint64, error) {
+	var latest *big.Int
+	for i, pr := range w.provers {
+		header, err := pr.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the latest header from %v: %v", w.endpoints[i], err)
+		}
+		if latest == nil || header.Number.Cmp(latest) < 0 {
+			latest = header.Number
+		}
+	}
+	return latest.Uint64(), nil
+}
+
+func (w *MisbehaviourWatcher) getLogger() *log.RelayLogger {
+	return log.GetLogger().WithModule(ModuleName)
+}
@@ -0,0 +1,55 @@
+package module
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+func TestMisbehaviourWatcherCheckHeight(t *testing.T) {
+	// the watcher logs the headers that cannot be obtained
+	if err := log.InitLogger("error", "text", "stderr"); err != nil {
+		t.Fatal(err)
+	}
+	chain1, chain2 := newTestForks(t, 2, nil)
+	pr := newTestProver(t, chain1)
+	lc := newTestLightClient(t, pr, 2)
+	trusted := lc.clientState.LatestHeight
+
+	w, err := NewMisbehaviourWatcher(pr, []string{newTestRPCServer(t, chain1), newTestRPCServer(t, chain2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// the endpoints agree on the blocks before the fork
+	if misbehaviour, err := w.CheckHeight(ctx, 2, trusted); err != nil {
+		t.Fatal(err)
+	} else if misbehaviour != nil {
+		t.Fatalf("unexpected misbehaviour before the fork: %v", misbehaviour)
+	}
+
+	misbehaviour, err := w.CheckHeight(ctx, 3, trusted)
+	if err != nil {
+		t.Fatal(err)
+	} else if misbehaviour == nil {
+		t.Fatal("the conflicting headers are not found")
+	}
+	if !misbehaviour.Header1.TrustedHeight.EQ(trusted) || !misbehaviour.Header2.TrustedHeight.EQ(trusted) {
+		t.Fatalf("unexpected trusted heights: %v %v", misbehaviour.Header1.TrustedHeight, misbehaviour.Header2.TrustedHeight)
+	}
+	// the misbehaviour built by the watcher freezes the client
+	if err := lc.update(misbehaviour, time.Unix(int64(chain1.Head().Time), 0)); err != nil {
+		t.Fatal(err)
+	}
+	if !lc.clientState.FrozenHeight.EQ(FrozenHeight) {
+		t.Fatalf("unexpected frozen height: expected=%v actual=%v", FrozenHeight, lc.clientState.FrozenHeight)
+	}
+
+	// no endpoint has the block
+	if _, err := w.CheckHeight(ctx, 4, trusted); err == nil {
+		t.Fatal("a missing block is checked")
+	}
+}